@@ -39,6 +39,13 @@ type TxFinder interface {
 	FindTxs(ctx context.Context, height uint64) ([]Tx, error)
 }
 
+// EarliestHeighter is implemented by TxFinders whose earliest block may be above height 1,
+// e.g. chains started with a genesis initial_height.
+type EarliestHeighter interface {
+	// EarliestHeight returns the height of the earliest available block, or 0 if there is no block yet.
+	EarliestHeight(ctx context.Context) (uint64, error)
+}
+
 // BlockSaver saves transactions for block at height.
 type BlockSaver interface {
 	SaveBlock(ctx context.Context, height uint64, txs []Tx) error
@@ -65,20 +72,26 @@ func NewCollector(log *zap.Logger, finder TxFinder, saver BlockSaver, rate time.
 	}
 }
 
-// Collect saves block transactions starting at height 1 and advancing by 1 height as long as there are
-// no errors with finding or saving the transactions.
+// Collect saves block transactions starting at height 1, or at the earliest height if the finder is an
+// EarliestHeighter, and advancing by 1 height as long as there are no errors with finding or saving the transactions.
 func (p *Collector) Collect(ctx context.Context) {
 	ctx, p.cancel = context.WithCancel(ctx)
 	defer p.cancel()
 
 	tick := time.NewTicker(p.rate)
 	defer tick.Stop()
-	var height uint64 = 1
+	var height uint64
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-tick.C:
+			if height == 0 {
+				// Unknown until the chain produced its first block.
+				if height = p.startHeight(ctx); height == 0 {
+					continue
+				}
+			}
 			if err := p.saveTxsForHeight(ctx, height); err != nil {
 				if strings.Contains(err.Error(), "must be less than or equal to the current blockchain height") {
 					// (I could not find a more precise way to match this error.)
@@ -102,6 +115,20 @@ func (p *Collector) Stop() {
 	p.cancel()
 }
 
+// startHeight returns the height to start collecting at, or 0 if it is not known yet.
+func (p *Collector) startHeight(ctx context.Context) uint64 {
+	finder, ok := p.finder.(EarliestHeighter)
+	if !ok {
+		return 1
+	}
+	height, err := finder.EarliestHeight(ctx)
+	if err != nil {
+		p.log.Info("Failed to get earliest height", zap.Error(err))
+		return 0
+	}
+	return height
+}
+
 func (p *Collector) saveTxsForHeight(ctx context.Context, height uint64) error {
 	txs, err := p.finder.FindTxs(ctx, height)
 	if err != nil {
@@ -0,0 +1,56 @@
+package blockdb
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockTxFinder struct {
+	earliest uint64
+}
+
+func (f mockTxFinder) FindTxs(ctx context.Context, height uint64) ([]Tx, error) {
+	return []Tx{{Data: []byte("tx")}}, nil
+}
+
+func (f mockTxFinder) EarliestHeight(ctx context.Context) (uint64, error) {
+	return f.earliest, nil
+}
+
+type mockBlockSaver struct {
+	mu      sync.Mutex
+	heights []uint64
+}
+
+func (s *mockBlockSaver) SaveBlock(ctx context.Context, height uint64, txs []Tx) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heights = append(s.heights, height)
+	return nil
+}
+
+func (s *mockBlockSaver) saved() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint64(nil), s.heights...)
+}
+
+func TestCollector_Collect(t *testing.T) {
+	t.Parallel()
+
+	// A chain started with a genesis initial height has no block below it.
+	saver := &mockBlockSaver{}
+	collector := NewCollector(zap.NewNop(), mockTxFinder{earliest: 100}, saver, time.Millisecond)
+	go collector.Collect(context.Background())
+	defer collector.Stop()
+
+	require.Eventually(t, func() bool {
+		return len(saver.saved()) >= 3
+	}, 10*time.Second, time.Millisecond)
+	require.Equal(t, []uint64{100, 101, 102}, saver.saved()[:3])
+}
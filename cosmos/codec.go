@@ -1,6 +1,7 @@
 package cosmos
 
 import (
+	"cosmossdk.io/x/evidence"
 	"cosmossdk.io/x/upgrade"
 	"github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
@@ -44,6 +45,7 @@ func DefaultEncoding() testutil.TestEncodingConfig {
 		params.AppModuleBasic{},
 		slashing.AppModuleBasic{},
 		upgrade.AppModuleBasic{},
+		evidence.AppModuleBasic{},
 		consensus.AppModuleBasic{},
 		transfer.AppModuleBasic{},
 		ibccore.AppModuleBasic{},
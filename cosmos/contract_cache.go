@@ -0,0 +1,74 @@
+package cosmos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ContractCache maps the sha256 checksum of wasm files to the code id they were stored under on a chain.
+// The zero value is ready to use.
+type ContractCache struct {
+	mu      sync.Mutex
+	codeIDs map[string]string
+}
+
+// Get returns the code id stored for checksum, if any.
+func (c *ContractCache) Get(checksum string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	codeID, ok := c.codeIDs[checksum]
+	return codeID, ok
+}
+
+// Set records that the wasm file with checksum was stored under codeID.
+func (c *ContractCache) Set(checksum, codeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.codeIDs == nil {
+		c.codeIDs = make(map[string]string)
+	}
+	c.codeIDs[checksum] = codeID
+}
+
+// storeOnce returns the code id stored for checksum, calling store to store the file and recording its code id
+// if there is none yet. The cache is locked during store so concurrent callers store each file only once.
+func (c *ContractCache) storeOnce(checksum string, store func() (string, error)) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if codeID, ok := c.codeIDs[checksum]; ok {
+		return codeID, nil
+	}
+	codeID, err := store()
+	if err != nil {
+		return "", err
+	}
+	if c.codeIDs == nil {
+		c.codeIDs = make(map[string]string)
+	}
+	c.codeIDs[checksum] = codeID
+	return codeID, nil
+}
+
+// StoreContractCached stores the contract at fileName with StoreContract, which returns the code id of an
+// earlier upload of a file with the same sha256 checksum on this chain instead of storing it again.
+func (node *Node) StoreContractCached(ctx context.Context, keyName, fileName string) (string, error) {
+	return node.StoreContract(ctx, keyName, fileName)
+}
+
+// storeContractCached stores the contract at fileName unless the chain's contract cache has its code id.
+func (node *Node) storeContractCached(ctx context.Context, chain *CosmosChain, keyName, fileName string) (string, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("reading contract file: %w", err)
+	}
+	sum := sha256.Sum256(content)
+
+	return chain.contracts.storeOnce(hex.EncodeToString(sum[:]), func() (string, error) {
+		codeID, _, err := node.StoreContractAndVerify(ctx, keyName, fileName)
+		return codeID, err
+	})
+}
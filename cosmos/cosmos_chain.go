@@ -8,6 +8,7 @@ import (
 	"io"
 	"math"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"sync"
@@ -50,6 +51,9 @@ type CosmosChain struct {
 	log      *zap.Logger
 	keyring  keyring.Keyring
 	findTxMu sync.Mutex
+
+	// contracts caches the code ids of contracts stored with StoreContract.
+	contracts ContractCache
 }
 
 func NewCosmosHeighlinerChainConfig(name string,
@@ -140,16 +144,7 @@ func (c *CosmosChain) AddFullNodes(ctx context.Context, configFileOverrides map[
 				if !ok {
 					return fmt.Errorf("Provided toml override for file %s is of type (%T). Expected (DecodedToml)", configFile, modifiedConfig)
 				}
-				if err := testutil.ModifyTomlConfigFile(
-					ctx,
-					fn.logger(),
-					fn.DockerClient,
-					fn.TestName,
-					fn.VolumeName,
-					fn.Chain.Config().Name,
-					configFile,
-					modifiedToml,
-				); err != nil {
+				if err := fn.ModifyTomlConfigFile(ctx, configFile, modifiedToml); err != nil {
 					return err
 				}
 			}
@@ -314,6 +309,11 @@ func (c *CosmosChain) SendFunds(ctx context.Context, keyName string, amount ibc.
 	return c.getFullNode().SendFunds(ctx, keyName, amount)
 }
 
+// CreateValidator creates a new validator operated by keyName, see Node.CreateValidator.
+func (c *CosmosChain) CreateValidator(ctx context.Context, keyName string, selfDelegation types.Coin, commissionRate string) (string, error) {
+	return c.getFullNode().CreateValidator(ctx, keyName, selfDelegation, commissionRate)
+}
+
 // Implements Chain interface
 func (c *CosmosChain) SendIBCTransfer(
 	ctx context.Context,
@@ -488,6 +488,7 @@ func (c *CosmosChain) txProposal(txHash string) (tx TxProposal, _ error) {
 }
 
 // StoreContract takes a file path to smart contract and stores it on-chain. Returns the contracts code id.
+// Files stored before on the chain are not stored again, see Node.StoreContract.
 func (c *CosmosChain) StoreContract(ctx context.Context, keyName string, fileName string, extraExecTxArgs ...string) (string, error) {
 	return c.getFullNode().StoreContract(ctx, keyName, fileName, extraExecTxArgs...)
 }
@@ -746,6 +747,10 @@ func (c *CosmosChain) StartHub(testName string, ctx context.Context, seq string,
 
 	configFileOverrides := chainCfg.ConfigFileOverrides
 
+	if err := c.Nodes().AssertUniqueHomeDirs(); err != nil {
+		return err
+	}
+
 	eg := new(errgroup.Group)
 	// Initialize config and sign gentx for each validator.
 	for _, v := range c.Validators {
@@ -760,16 +765,7 @@ func (c *CosmosChain) StartHub(testName string, ctx context.Context, seq string,
 				if !ok {
 					return fmt.Errorf("Provided toml override for file %s is of type (%T). Expected (DecodedToml)", configFile, modifiedConfig)
 				}
-				if err := testutil.ModifyTomlConfigFile(
-					ctx,
-					v.logger(),
-					v.DockerClient,
-					v.TestName,
-					v.VolumeName,
-					v.Chain.Config().Name,
-					configFile,
-					modifiedToml,
-				); err != nil {
+				if err := v.ModifyTomlConfigFile(ctx, configFile, modifiedToml); err != nil {
 					return err
 				}
 			}
@@ -793,16 +789,7 @@ func (c *CosmosChain) StartHub(testName string, ctx context.Context, seq string,
 				if !ok {
 					return fmt.Errorf("Provided toml override for file %s is of type (%T). Expected (DecodedToml)", configFile, modifiedConfig)
 				}
-				if err := testutil.ModifyTomlConfigFile(
-					ctx,
-					n.logger(),
-					n.DockerClient,
-					n.TestName,
-					n.VolumeName,
-					n.Chain.Config().Name,
-					configFile,
-					modifiedToml,
-				); err != nil {
+				if err := n.ModifyTomlConfigFile(ctx, configFile, modifiedToml); err != nil {
 					return err
 				}
 			}
@@ -926,6 +913,10 @@ func (c *CosmosChain) StartHub(testName string, ctx context.Context, seq string,
 		return err
 	}
 
+	// The sequencer key is shared with the rollapp through its sequencer keyring.
+	for _, n := range c.Nodes() {
+		n.KeyringDir = path.Join(keyDir, "sequencer_keys")
+	}
 	if err := c.CreateHubKey(ctx, "sequencer"); err != nil {
 		return err
 	}
@@ -942,10 +933,10 @@ func (c *CosmosChain) StartHub(testName string, ctx context.Context, seq string,
 	if err := c.SendFunds(ctx, "faucet", fund); err != nil {
 		return err
 	}
-	if err := c.RegisterRollAppToHub(ctx, "sequencer", "demo-dymension-rollapp", "5", keyDir); err != nil {
+	if err := c.RegisterRollAppToHub(ctx, "sequencer", "demo-dymension-rollapp", "5", sequencer); err != nil {
 		return fmt.Errorf("failed to start chain %s: %w", c.Config().Name, err)
 	}
-	if err := c.RegisterSequencerToHub(ctx, "sequencer", "demo-dymension-rollapp", "5", seq, keyDir); err != nil {
+	if err := c.RegisterSequencerToHub(ctx, "sequencer", "demo-dymension-rollapp", "5", seq); err != nil {
 		return fmt.Errorf("failed to start chain %s: %w", c.Config().Name, err)
 	}
 	return nil
@@ -975,6 +966,10 @@ func (c *CosmosChain) CreateRollapp(testName string, ctx context.Context, additi
 
 	configFileOverrides := chainCfg.ConfigFileOverrides
 
+	if err := c.Nodes().AssertUniqueHomeDirs(); err != nil {
+		return "", err
+	}
+
 	eg := new(errgroup.Group)
 	// Initialize config and sign gentx for each validator.
 	for _, v := range c.Validators {
@@ -990,16 +985,7 @@ func (c *CosmosChain) CreateRollapp(testName string, ctx context.Context, additi
 				if !ok {
 					return fmt.Errorf("Provided toml override for file %s is of type (%T). Expected (DecodedToml)", configFile, modifiedConfig)
 				}
-				if err := testutil.ModifyTomlConfigFile(
-					ctx,
-					v.logger(),
-					v.DockerClient,
-					v.TestName,
-					v.VolumeName,
-					v.Chain.Config().Name,
-					configFile,
-					modifiedToml,
-				); err != nil {
+				if err := v.ModifyTomlConfigFile(ctx, configFile, modifiedToml); err != nil {
 					return err
 				}
 			}
@@ -1023,16 +1009,7 @@ func (c *CosmosChain) CreateRollapp(testName string, ctx context.Context, additi
 				if !ok {
 					return fmt.Errorf("Provided toml override for file %s is of type (%T). Expected (DecodedToml)", configFile, modifiedConfig)
 				}
-				if err := testutil.ModifyTomlConfigFile(
-					ctx,
-					n.logger(),
-					n.DockerClient,
-					n.TestName,
-					n.VolumeName,
-					n.Chain.Config().Name,
-					configFile,
-					modifiedToml,
-				); err != nil {
+				if err := n.ModifyTomlConfigFile(ctx, configFile, modifiedToml); err != nil {
 					return err
 				}
 			}
@@ -1164,13 +1141,13 @@ func (c *CosmosChain) StartRollapp(testName string, ctx context.Context, additio
 }
 
 // RegisterSequencerToHub register sequencer for rollapp on settlement.
-func (c *CosmosChain) RegisterSequencerToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, seq, keyDir string) error {
-	return c.GetNode().RegisterSequencerToHub(ctx, keyName, rollappChainID, maxSequencers, seq, keyDir)
+func (c *CosmosChain) RegisterSequencerToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, seq string) error {
+	return c.GetNode().RegisterSequencerToHub(ctx, keyName, rollappChainID, maxSequencers, seq)
 }
 
 // RegisterRollAppToHub register rollapp on settlement.
-func (c *CosmosChain) RegisterRollAppToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, keyDir string) error {
-	return c.GetNode().RegisterRollAppToHub(ctx, keyName, rollappChainID, maxSequencers, keyDir)
+func (c *CosmosChain) RegisterRollAppToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, initialSequencerAddr string) error {
+	return c.GetNode().RegisterRollAppToHub(ctx, keyName, rollappChainID, maxSequencers, initialSequencerAddr)
 }
 
 // ShowSeq will show sequencer addr
@@ -1183,6 +1160,11 @@ func (c *CosmosChain) Height(ctx context.Context) (uint64, error) {
 	return c.getFullNode().Height(ctx)
 }
 
+// EarliestHeight implements blockdb.EarliestHeighter.
+func (c *CosmosChain) EarliestHeight(ctx context.Context) (uint64, error) {
+	return c.getFullNode().EarliestHeight(ctx)
+}
+
 // Acknowledgements implements ibc.Chain, returning all acknowledgments in block at height
 func (c *CosmosChain) Acknowledgements(ctx context.Context, height uint64) ([]ibc.PacketAcknowledgement, error) {
 	var acks []*chanTypes.MsgAcknowledgement
@@ -1257,6 +1239,7 @@ func (c *CosmosChain) FindTxs(ctx context.Context, height uint64) ([]blockdb.Tx,
 }
 
 // StopAllNodes stops and removes all long running containers (validators and full nodes)
+// and releases the claims of the nodes on their home dirs, see Nodes.AssertUniqueHomeDirs.
 func (c *CosmosChain) StopAllNodes(ctx context.Context) error {
 	var eg errgroup.Group
 	for _, n := range c.Nodes() {
@@ -1265,18 +1248,25 @@ func (c *CosmosChain) StopAllNodes(ctx context.Context) error {
 			if err := n.StopContainer(ctx); err != nil {
 				return err
 			}
-			return n.RemoveContainer(ctx)
+			if err := n.RemoveContainer(ctx); err != nil {
+				return err
+			}
+			n.releaseHomeDir()
+			return nil
 		})
 	}
 	return eg.Wait()
 }
 
-// StartAllNodes creates and starts new containers for each node.
+// StartAllNodes creates and starts new containers for each node, claiming their home dirs again.
 // Should only be used if the chain has previously been started with .Start.
 func (c *CosmosChain) StartAllNodes(ctx context.Context) error {
 	// prevent client calls during this time
 	c.findTxMu.Lock()
 	defer c.findTxMu.Unlock()
+	if err := c.Nodes().AssertUniqueHomeDirs(); err != nil {
+		return err
+	}
 	var eg errgroup.Group
 	for _, n := range c.Nodes() {
 		n := n
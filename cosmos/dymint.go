@@ -0,0 +1,100 @@
+package cosmos
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	batchStartHeightRe = regexp.MustCompile(`start[ _]?height"?[=:]\s*"?(\d+)`)
+	batchEndHeightRe   = regexp.MustCompile(`end[ _]?height"?[=:]\s*"?(\d+)`)
+)
+
+// LastBatchInfo returns the height range and size of the last batch the rollapp node submitted to the settlement layer.
+// The range is read from the latest state update of the rollapp on the hub, the size is the sum of the sizes of the
+// blocks in the batch. As a last resort when no hub node is given, the range is scraped from dymint's batch
+// submission log, which breaks if dymint changes its log format.
+func (node *Node) LastBatchInfo(ctx context.Context, hub *Node) (*BatchInfo, error) {
+	var (
+		info *BatchInfo
+		err  error
+	)
+	if hub != nil {
+		info, err = node.lastBatchRangeFromHub(ctx, hub)
+	} else {
+		info, err = node.lastBatchRangeFromLogs(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for h := int64(info.StartHeight); h <= int64(info.EndHeight); h++ {
+		height := h
+		block, err := node.Client.Block(ctx, &height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %d: %w", h, err)
+		}
+		info.Size += block.Block.Size()
+	}
+	return info, nil
+}
+
+// lastBatchRangeFromHub returns the height range of the latest state update of the rollapp on the hub.
+func (node *Node) lastBatchRangeFromHub(ctx context.Context, hub *Node) (*BatchInfo, error) {
+	rollappChainID := node.Chain.Config().ChainID
+	stateInfo, err := hub.QueryLatestStateInfo(ctx, rollappChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest state info of %s: %w", rollappChainID, err)
+	}
+	startHeight, err := strconv.ParseUint(stateInfo.StartHeight, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start height %q: %w", stateInfo.StartHeight, err)
+	}
+	numBlocks, err := strconv.ParseUint(stateInfo.NumBlocks, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number of blocks %q: %w", stateInfo.NumBlocks, err)
+	}
+	if numBlocks == 0 {
+		return nil, errors.New("no batch submitted yet")
+	}
+	return &BatchInfo{StartHeight: startHeight, EndHeight: startHeight + numBlocks - 1}, nil
+}
+
+// lastBatchRangeFromLogs returns the height range of the last batch logged as submitted by dymint.
+func (node *Node) lastBatchRangeFromLogs(ctx context.Context) (*BatchInfo, error) {
+	logs, err := node.containerLifecycle.Logs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read container logs: %w", err)
+	}
+
+	var info *BatchInfo
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lower := strings.ToLower(line)
+		if !strings.Contains(lower, "submitted batch") {
+			continue
+		}
+		start, end := batchStartHeightRe.FindStringSubmatch(lower), batchEndHeightRe.FindStringSubmatch(lower)
+		if start == nil || end == nil {
+			continue
+		}
+		startHeight, _ := strconv.ParseUint(start[1], 10, 64)
+		endHeight, _ := strconv.ParseUint(end[1], 10, 64)
+		info = &BatchInfo{StartHeight: startHeight, EndHeight: endHeight}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, errors.New("no batch submitted yet")
+	}
+	return info, nil
+}
@@ -1,9 +1,16 @@
 package cosmos
 
 import (
+	"context"
 	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
 
 	abcitypes "github.com/cometbft/cometbft/abci/types"
+	"github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/decentrio/rollup-e2e-testing/blockdb"
 )
 
 // AttributeValue returns an event attribute value given the eventType and attribute key tuple.
@@ -35,3 +42,107 @@ func AttributeValue(events []abcitypes.Event, eventType, attrKey string) (string
 	}
 	return "", false
 }
+
+// AssertEventOrder returns an error unless the events of the tx include the given event types in the given
+// relative order. Other events may appear before, between or after them.
+func (node *Node) AssertEventOrder(ctx context.Context, txHash string, eventTypesInOrder []string) error {
+	txResp, err := node.GetTransaction(node.CliContext(), txHash)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+
+	next := 0
+	for _, event := range txResp.Events {
+		if next == len(eventTypesInOrder) {
+			break
+		}
+		if event.Type == eventTypesInOrder[next] {
+			next++
+		}
+	}
+	if next < len(eventTypesInOrder) {
+		return fmt.Errorf("tx %s does not emit events %v in order: %q not found after %v",
+			txHash, eventTypesInOrder, eventTypesInOrder[next], eventTypesInOrder[:next])
+	}
+	return nil
+}
+
+// contractAddressAttr is the attribute wasmd adds to every custom contract event with the emitting contract.
+const contractAddressAttr = "_contract_address"
+
+// AssertContractEvent returns an error unless the tx emits the custom contract event eventName, i.e. an event of
+// type "wasm-<eventName>", with all of the given attributes. If attrs holds _contract_address, only events emitted
+// by that contract are considered, so the assertion holds when several contracts emit the same event.
+func (node *Node) AssertContractEvent(ctx context.Context, txResp *types.TxResponse, eventName string, attrs map[string]string) error {
+	eventType := "wasm-" + strings.TrimPrefix(eventName, "wasm-")
+	contract, byContract := attrs[contractAddressAttr]
+
+	var mismatches []string
+	for _, event := range txResp.Events {
+		if event.Type != eventType {
+			continue
+		}
+		if byContract {
+			if emitter, _ := AttributeValue([]abcitypes.Event{event}, eventType, contractAddressAttr); emitter != contract {
+				continue
+			}
+		}
+
+		var mismatch []string
+		for key, want := range attrs {
+			got, ok := AttributeValue([]abcitypes.Event{event}, eventType, key)
+			switch {
+			case !ok:
+				mismatch = append(mismatch, fmt.Sprintf("%s missing", key))
+			case got != want:
+				mismatch = append(mismatch, fmt.Sprintf("%s=%q, want %q", key, got, want))
+			}
+		}
+		if len(mismatch) == 0 {
+			return nil
+		}
+		sort.Strings(mismatch)
+		mismatches = append(mismatches, strings.Join(mismatch, ", "))
+	}
+
+	if len(mismatches) == 0 {
+		if byContract {
+			return fmt.Errorf("tx %s has no %s event emitted by contract %s", txResp.TxHash, eventType, contract)
+		}
+		return fmt.Errorf("tx %s has no %s event", txResp.TxHash, eventType)
+	}
+	return fmt.Errorf("no %s event of tx %s has the expected attributes: %s", eventType, txResp.TxHash, strings.Join(mismatches, "; "))
+}
+
+// FindEventAttribute returns the value of the first attribute attrKey of an event of type eventType,
+// e.g. the packet_sequence of a send_packet event, and whether it was found.
+func FindEventAttribute(events []blockdb.Event, eventType, attrKey string) (string, bool) {
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == attrKey {
+				return attr.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FindAllEventAttributes returns the values of all attributes attrKey of events of type eventType, in event order,
+// e.g. the packet_sequence of every send_packet event of a tx sending several packets.
+func FindAllEventAttributes(events []blockdb.Event, eventType, attrKey string) []string {
+	var values []string
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == attrKey {
+				values = append(values, attr.Value)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,37 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/decentrio/rollup-e2e-testing/blockdb"
+)
+
+func TestFindEventAttribute(t *testing.T) {
+	t.Parallel()
+
+	events := []blockdb.Event{
+		{Type: "message", Attributes: []blockdb.EventAttribute{{Key: "action", Value: "/ibc.applications.transfer.v1.MsgTransfer"}}},
+		{Type: "send_packet", Attributes: []blockdb.EventAttribute{
+			{Key: "packet_src_channel", Value: "channel-0"},
+			{Key: "packet_sequence", Value: "1"},
+		}},
+		{Type: "send_packet", Attributes: []blockdb.EventAttribute{
+			{Key: "packet_src_channel", Value: "channel-1"},
+			{Key: "packet_sequence", Value: "7"},
+		}},
+	}
+
+	value, ok := FindEventAttribute(events, "send_packet", "packet_sequence")
+	require.True(t, ok)
+	require.Equal(t, "1", value)
+
+	_, ok = FindEventAttribute(events, "send_packet", "packet_data")
+	require.False(t, ok)
+	_, ok = FindEventAttribute(events, "recv_packet", "packet_sequence")
+	require.False(t, ok)
+
+	require.Equal(t, []string{"1", "7"}, FindAllEventAttributes(events, "send_packet", "packet_sequence"))
+	require.Empty(t, FindAllEventAttributes(events, "recv_packet", "packet_sequence"))
+}
@@ -1,13 +1,20 @@
 package cosmos
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+
 	"github.com/decentrio/rollup-e2e-testing/ibc"
 	"github.com/icza/dyno"
+	"golang.org/x/mod/semver"
 )
 
 type GenesisKV struct {
@@ -53,3 +60,464 @@ func ModifyGenesis(genesisKV []GenesisKV) func(ibc.ChainConfig, []byte) ([]byte,
 		return out, nil
 	}
 }
+
+// DiffGenesis compares two genesis files and returns the paths of the app_state modules that differ,
+// e.g. "app_state.bank". Modules present in only one of the files are reported as differing.
+// The returned paths are sorted.
+func DiffGenesis(a, b []byte) ([]string, error) {
+	appStateA, err := genesisAppState(a)
+	if err != nil {
+		return nil, fmt.Errorf("first genesis: %w", err)
+	}
+	appStateB, err := genesisAppState(b)
+	if err != nil {
+		return nil, fmt.Errorf("second genesis: %w", err)
+	}
+
+	modules := make(map[string]struct{}, len(appStateA))
+	for module := range appStateA {
+		modules[module] = struct{}{}
+	}
+	for module := range appStateB {
+		modules[module] = struct{}{}
+	}
+
+	var diff []string
+	for module := range modules {
+		if !reflect.DeepEqual(appStateA[module], appStateB[module]) {
+			diff = append(diff, "app_state."+module)
+		}
+	}
+	sort.Strings(diff)
+	return diff, nil
+}
+
+func genesisAppState(genbz []byte) (map[string]interface{}, error) {
+	var g struct {
+		AppState map[string]interface{} `json:"app_state"`
+	}
+	if err := json.Unmarshal(genbz, &g); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal genesis file: %w", err)
+	}
+	return g.AppState, nil
+}
+
+// updateGenesisFile reads the node's genesis file, applies update to the decoded json and writes it back.
+func (node *Node) updateGenesisFile(ctx context.Context, update func(g map[string]interface{}) error) error {
+	genbz, err := node.GenesisFileContent(ctx)
+	if err != nil {
+		return err
+	}
+
+	g := make(map[string]interface{})
+	if err := json.Unmarshal(genbz, &g); err != nil {
+		return fmt.Errorf("failed to unmarshal genesis file: %w", err)
+	}
+
+	if err := update(g); err != nil {
+		return err
+	}
+
+	out, err := json.Marshal(g)
+	if err != nil {
+		return fmt.Errorf("failed to marshal genesis bytes to json: %w", err)
+	}
+	return node.OverwriteGenesisFile(ctx, out)
+}
+
+// SetInitialHeight sets the initial_height field of the node's genesis file,
+// so the chain produces its first block at height instead of 1.
+// It must be called on every node of the chain before the containers are started.
+func (node *Node) SetInitialHeight(ctx context.Context, height int64) error {
+	if height < 1 {
+		return fmt.Errorf("initial height must be at least 1, got %d", height)
+	}
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		// Older genesis files encode the height as a string, newer ones as a number.
+		if _, ok := g["initial_height"].(string); ok {
+			g["initial_height"] = strconv.FormatInt(height, 10)
+		} else {
+			g["initial_height"] = height
+		}
+		return nil
+	})
+}
+
+// SetMinCommissionRate sets the minimum commission rate validators may be created with in the staking genesis params.
+func (node *Node) SetMinCommissionRate(ctx context.Context, rate math.LegacyDec) error {
+	if rate.IsNegative() || rate.GT(math.LegacyOneDec()) {
+		return fmt.Errorf("min commission rate must be between 0 and 1, got %s", rate)
+	}
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		return dyno.Set(g, rate.String(), "app_state", "staking", "params", "min_commission_rate")
+	})
+}
+
+// SetMinDeposit sets the minimum deposit for a proposal to enter the voting period in the gov genesis params.
+func (node *Node) SetMinDeposit(ctx context.Context, amount types.Coins) error {
+	if !amount.IsValid() {
+		return fmt.Errorf("invalid min deposit %q", amount)
+	}
+	minDeposit := make([]interface{}, len(amount))
+	for i, coin := range amount {
+		minDeposit[i] = map[string]interface{}{"denom": coin.Denom, "amount": coin.Amount.String()}
+	}
+
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		set := false
+		// gov v1 keeps the deposit params in params, legacy gov in deposit_params, which SDK v47 still carries.
+		if _, err := dyno.GetMapS(g, "app_state", "gov", "params"); err == nil {
+			if err := dyno.Set(g, minDeposit, "app_state", "gov", "params", "min_deposit"); err != nil {
+				return err
+			}
+			set = true
+		}
+		if _, err := dyno.GetMapS(g, "app_state", "gov", "deposit_params"); err == nil {
+			if err := dyno.Set(g, minDeposit, "app_state", "gov", "deposit_params", "min_deposit"); err != nil {
+				return err
+			}
+			set = true
+		}
+		if !set {
+			return fmt.Errorf("gov genesis has neither params nor deposit_params")
+		}
+		return nil
+	})
+}
+
+// SetDenomExponent sets the exponent of the display unit of denom in its bank genesis denom metadata,
+// e.g. 18 for an EVM native token or 6 for a typical Cosmos token. The base unit keeps exponent 0.
+func (node *Node) SetDenomExponent(ctx context.Context, denom string, exponent uint32) error {
+	if exponent == 0 {
+		return fmt.Errorf("display exponent must be positive")
+	}
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		metadatas, err := dyno.GetSlice(g, "app_state", "bank", "denom_metadata")
+		if err != nil {
+			return fmt.Errorf("failed to get denom metadata: %w", err)
+		}
+		for _, m := range metadatas {
+			metadata, ok := m.(map[string]interface{})
+			if !ok || metadata["base"] != denom {
+				continue
+			}
+			display, _ := metadata["display"].(string)
+			if display == "" || display == denom {
+				return fmt.Errorf("denom metadata of %s has no display unit", denom)
+			}
+
+			units, err := dyno.GetSlice(metadata, "denom_units")
+			if err != nil {
+				return fmt.Errorf("failed to get denom units of %s: %w", denom, err)
+			}
+			for _, u := range units {
+				unit, ok := u.(map[string]interface{})
+				if ok && unit["denom"] == display {
+					unit["exponent"] = exponent
+					return nil
+				}
+			}
+			return fmt.Errorf("denom metadata of %s has no unit for display denom %s", denom, display)
+		}
+		return fmt.Errorf("no denom metadata for %s in genesis", denom)
+	})
+}
+
+// GenesisAccount is an account funded in genesis by BuildGenesisWithAccounts.
+type GenesisAccount struct {
+	Address string
+	Coins   types.Coins
+}
+
+// BuildGenesisWithAccounts adds accounts and their balances to the auth and bank sections of the node's genesis
+// file in a single write, updating the bank supply accordingly, and validates the result with the chain binary.
+// Unlike AddGenesisAccount it does not run one CLI call per account, so it scales to thousands of accounts.
+func (node *Node) BuildGenesisWithAccounts(ctx context.Context, accounts []GenesisAccount) error {
+	err := node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		authAccounts, err := dyno.GetSlice(g, "app_state", "auth", "accounts")
+		if err != nil {
+			return fmt.Errorf("failed to get auth accounts: %w", err)
+		}
+		balances, err := dyno.GetSlice(g, "app_state", "bank", "balances")
+		if err != nil {
+			return fmt.Errorf("failed to get bank balances: %w", err)
+		}
+		supply, err := genesisCoins(g, "app_state", "bank", "supply")
+		if err != nil {
+			return fmt.Errorf("failed to get bank supply: %w", err)
+		}
+
+		// New accounts are numbered after the existing ones, which may be nested in module or vesting accounts.
+		existing := make(map[string]bool, len(authAccounts)+len(balances))
+		var nextNumber uint64
+		for _, raw := range authAccounts {
+			base, ok := genesisBaseAccount(raw)
+			if !ok {
+				continue
+			}
+			if address, ok := base["address"].(string); ok {
+				existing[address] = true
+			}
+			number, err := strconv.ParseUint(fmt.Sprint(base["account_number"]), 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid account number of genesis account %v: %w", base["address"], err)
+			}
+			if number >= nextNumber {
+				nextNumber = number + 1
+			}
+		}
+		for _, raw := range balances {
+			if balance, ok := raw.(map[string]interface{}); ok {
+				if address, ok := balance["address"].(string); ok {
+					existing[address] = true
+				}
+			}
+		}
+
+		for _, acc := range accounts {
+			if _, err := types.GetFromBech32(acc.Address, node.Chain.Config().Bech32Prefix); err != nil {
+				return fmt.Errorf("invalid genesis account address %s: %w", acc.Address, err)
+			}
+			if existing[acc.Address] {
+				return fmt.Errorf("genesis account %s already exists", acc.Address)
+			}
+			existing[acc.Address] = true
+
+			authAccounts = append(authAccounts, map[string]interface{}{
+				"@type":          "/cosmos.auth.v1beta1.BaseAccount",
+				"address":        acc.Address,
+				"pub_key":        nil,
+				"account_number": strconv.FormatUint(nextNumber, 10),
+				"sequence":       "0",
+			})
+			nextNumber++
+
+			coins := make([]interface{}, len(acc.Coins))
+			for i, c := range acc.Coins {
+				coins[i] = map[string]interface{}{"denom": c.Denom, "amount": c.Amount.String()}
+			}
+			balances = append(balances, map[string]interface{}{
+				"address": acc.Address,
+				"coins":   coins,
+			})
+			supply = supply.Add(acc.Coins...)
+		}
+
+		supplyJSON := make([]interface{}, len(supply))
+		for i, c := range supply {
+			supplyJSON[i] = map[string]interface{}{"denom": c.Denom, "amount": c.Amount.String()}
+		}
+
+		if err := dyno.Set(g, authAccounts, "app_state", "auth", "accounts"); err != nil {
+			return err
+		}
+		if err := dyno.Set(g, balances, "app_state", "bank", "balances"); err != nil {
+			return err
+		}
+		return dyno.Set(g, supplyJSON, "app_state", "bank", "supply")
+	})
+	if err != nil {
+		return err
+	}
+
+	// SDK v50 names the command genesis validate, SDK v47 genesis validate-genesis, older SDKs validate-genesis.
+	var stderr []byte
+	for _, command := range [][]string{{"genesis", "validate"}, {"genesis", "validate-genesis"}, {"validate-genesis"}} {
+		_, stderr, err = node.ExecBin(ctx, command...)
+		if err == nil || !strings.Contains(err.Error(), "unknown command") {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("generated genesis is invalid (stderr=%q): %w", stderr, err)
+	}
+	return nil
+}
+
+// genesisBaseAccount returns the base account of a genesis auth account, which module and vesting accounts nest.
+func genesisBaseAccount(account interface{}) (map[string]interface{}, bool) {
+	acc, ok := account.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if _, ok := acc["account_number"]; ok {
+		return acc, true
+	}
+	for _, key := range []string{"base_account", "base_vesting_account"} {
+		if nested, ok := acc[key]; ok {
+			return genesisBaseAccount(nested)
+		}
+	}
+	return nil, false
+}
+
+// genesisCoins decodes the coins at path in the genesis json.
+func genesisCoins(g interface{}, path ...interface{}) (types.Coins, error) {
+	raw, err := dyno.Get(g, path...)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var coins types.Coins
+	if err := json.Unmarshal(bz, &coins); err != nil {
+		return nil, err
+	}
+	return coins, nil
+}
+
+// SetBondDenom sets the staking bond denom in the node's genesis file, along with the mint denom and the
+// crisis constant fee denom if those modules are present. It must be called after the genesis accounts were
+// added, since it returns an error unless some genesis account holds denom, as gentx would fail otherwise.
+func (node *Node) SetBondDenom(ctx context.Context, denom string) error {
+	if err := types.ValidateDenom(denom); err != nil {
+		return err
+	}
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		balances, err := dyno.GetSlice(g, "app_state", "bank", "balances")
+		if err != nil {
+			return fmt.Errorf("failed to get bank balances: %w", err)
+		}
+		funded := false
+		for _, b := range balances {
+			coins, err := genesisCoins(b, "coins")
+			if err != nil {
+				return fmt.Errorf("failed to decode genesis balance: %w", err)
+			}
+			if coins.AmountOf(denom).IsPositive() {
+				funded = true
+				break
+			}
+		}
+		if !funded {
+			return fmt.Errorf("no genesis account holds bond denom %s", denom)
+		}
+
+		if err := dyno.Set(g, denom, "app_state", "staking", "params", "bond_denom"); err != nil {
+			return err
+		}
+		if _, err := dyno.Get(g, "app_state", "mint", "params", "mint_denom"); err == nil {
+			if err := dyno.Set(g, denom, "app_state", "mint", "params", "mint_denom"); err != nil {
+				return err
+			}
+		}
+		if _, err := dyno.Get(g, "app_state", "crisis", "constant_fee", "denom"); err == nil {
+			if err := dyno.Set(g, denom, "app_state", "crisis", "constant_fee", "denom"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetBlockMaxGas sets the maximum gas of a block in the consensus params of the node's genesis file.
+// -1 means unlimited.
+func (node *Node) SetBlockMaxGas(ctx context.Context, maxGas int64) error {
+	if maxGas < -1 || maxGas == 0 {
+		return fmt.Errorf("block max gas must be -1 or positive, got %d", maxGas)
+	}
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		// SDK v50 genesis files moved the consensus params under consensus.params.
+		if _, err := dyno.Get(g, "consensus", "params", "block"); err == nil {
+			return dyno.Set(g, strconv.FormatInt(maxGas, 10), "consensus", "params", "block", "max_gas")
+		}
+		return dyno.Set(g, strconv.FormatInt(maxGas, 10), "consensus_params", "block", "max_gas")
+	})
+}
+
+// wasmdModule is the module path of wasmd in the build dependencies of CosmWasm chains.
+const wasmdModule = "github.com/CosmWasm/wasmd"
+
+// wasmd releases changing the wasm genesis params set by the helpers below, taken from the Params message and the
+// AccessType enum in the proto definitions of the tagged releases.
+const (
+	wasmMaxCodeSizeRemovedIn  = "v0.27.0"
+	wasmAnyOfAddressesAddedIn = "v0.29.0"
+	wasmOnlyAddressRemovedIn  = "v0.41.0"
+)
+
+// SetWasmInstantiatePermission sets the default instantiate permission of uploaded codes in the wasm genesis params,
+// one of "Everybody", "Nobody", "AnyOfAddresses" (wasmd v0.29.0 and later) or "OnlyAddress" (before wasmd v0.41.0).
+func (node *Node) SetWasmInstantiatePermission(ctx context.Context, permission string) error {
+	version, err := node.wasmdVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if err := checkWasmInstantiatePermission(version, permission); err != nil {
+		return err
+	}
+	return node.setWasmParam(ctx, "instantiate_default_permission", permission)
+}
+
+// SetWasmMaxContractSize sets the maximum size in bytes of uploaded wasm codes in the wasm genesis params.
+// wasmd v0.27.0 and later hard code the limit, so it fails on such chains.
+func (node *Node) SetWasmMaxContractSize(ctx context.Context, size uint64) error {
+	if size == 0 {
+		return fmt.Errorf("max contract size must be positive")
+	}
+	version, err := node.wasmdVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if semver.Compare(version, wasmMaxCodeSizeRemovedIn) >= 0 {
+		return fmt.Errorf("wasm param max_wasm_code_size was removed in wasmd %s, chain runs %s", wasmMaxCodeSizeRemovedIn, version)
+	}
+	return node.setWasmParam(ctx, "max_wasm_code_size", strconv.FormatUint(size, 10))
+}
+
+// checkWasmInstantiatePermission returns an error unless wasmd version accepts the instantiate permission.
+func checkWasmInstantiatePermission(version, permission string) error {
+	switch permission {
+	case "Everybody", "Nobody":
+	case "AnyOfAddresses":
+		if semver.Compare(version, wasmAnyOfAddressesAddedIn) < 0 {
+			return fmt.Errorf("instantiate permission %s requires wasmd %s, chain runs %s", permission, wasmAnyOfAddressesAddedIn, version)
+		}
+	case "OnlyAddress":
+		if semver.Compare(version, wasmOnlyAddressRemovedIn) >= 0 {
+			return fmt.Errorf("instantiate permission %s was removed in wasmd %s, chain runs %s", permission, wasmOnlyAddressRemovedIn, version)
+		}
+	default:
+		return fmt.Errorf("unknown instantiate permission %q", permission)
+	}
+	return nil
+}
+
+// wasmdVersion returns the version of wasmd the chain binary is built with, honoring replacements.
+func (node *Node) wasmdVersion(ctx context.Context) (string, error) {
+	info := node.GetBuildInformation(ctx)
+	if info == nil {
+		return "", fmt.Errorf("failed to get build information of %s", node.Chain.Config().Bin)
+	}
+	return wasmdVersionOf(info.BuildDeps)
+}
+
+// wasmdVersionOf returns the version of wasmd in deps, honoring replacements.
+func wasmdVersionOf(deps []BuildDependency) (string, error) {
+	for _, dep := range deps {
+		if dep.Parent != wasmdModule {
+			continue
+		}
+		version := dep.Version
+		if dep.IsReplacement && dep.ReplacementVersion != "" {
+			version = dep.ReplacementVersion
+		}
+		if !semver.IsValid(version) {
+			return "", fmt.Errorf("invalid wasmd version %q", version)
+		}
+		return version, nil
+	}
+	return "", fmt.Errorf("binary is not built with %s", wasmdModule)
+}
+
+// setWasmParam sets the wasm genesis param key to value, failing if the genesis has no such param.
+func (node *Node) setWasmParam(ctx context.Context, key, value string) error {
+	return node.updateGenesisFile(ctx, func(g map[string]interface{}) error {
+		if _, err := dyno.Get(g, "app_state", "wasm", "params", key); err != nil {
+			return fmt.Errorf("wasm genesis params of %s have no %s: %w", node.Chain.Config().ChainID, key, err)
+		}
+		return dyno.Set(g, value, "app_state", "wasm", "params", key)
+	})
+}
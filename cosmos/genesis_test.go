@@ -0,0 +1,68 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffGenesis(t *testing.T) {
+	t.Parallel()
+
+	a := []byte(`{"chain_id":"a","app_state":{"bank":{"supply":[]},"gov":{"params":{"quorum":"0.33"}},"auth":{}}}`)
+	b := []byte(`{"chain_id":"b","app_state":{"bank":{"supply":[]},"gov":{"params":{"quorum":"0.5"}},"rollapp":{}}}`)
+
+	diff, err := DiffGenesis(a, b)
+	require.NoError(t, err)
+	require.Equal(t, []string{"app_state.auth", "app_state.gov", "app_state.rollapp"}, diff)
+
+	diff, err = DiffGenesis(a, a)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	_, err = DiffGenesis(a, []byte(`not json`))
+	require.Error(t, err)
+}
+
+func TestGenesisBaseAccount(t *testing.T) {
+	t.Parallel()
+
+	var accounts []interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"@type":"/cosmos.auth.v1beta1.BaseAccount","address":"a","account_number":"3"},
+		{"@type":"/cosmos.auth.v1beta1.ModuleAccount","base_account":{"address":"b","account_number":"7"},"name":"gov"},
+		{"@type":"/cosmos.vesting.v1beta1.DelayedVestingAccount","base_vesting_account":{"base_account":{"address":"c","account_number":"5"}}},
+		{"@type":"/unknown"}
+	]`), &accounts))
+
+	var got []string
+	for _, acc := range accounts {
+		base, ok := genesisBaseAccount(acc)
+		if !ok {
+			continue
+		}
+		got = append(got, base["address"].(string)+"="+base["account_number"].(string))
+	}
+	require.Equal(t, []string{"a=3", "b=7", "c=5"}, got)
+}
+
+func TestWasmdVersion(t *testing.T) {
+	t.Parallel()
+
+	version, err := wasmdVersionOf([]BuildDependency{
+		{Parent: "github.com/cosmos/cosmos-sdk", Version: "v0.47.5"},
+		{Parent: wasmdModule, Version: "v0.40.0", IsReplacement: true, Replacement: "github.com/fork/wasmd", ReplacementVersion: "v0.41.0"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "v0.41.0", version)
+
+	_, err = wasmdVersionOf([]BuildDependency{{Parent: "github.com/cosmos/cosmos-sdk", Version: "v0.47.5"}})
+	require.ErrorContains(t, err, "not built with")
+
+	require.NoError(t, checkWasmInstantiatePermission("v0.40.0", "OnlyAddress"))
+	require.ErrorContains(t, checkWasmInstantiatePermission("v0.41.0", "OnlyAddress"), "was removed in wasmd v0.41.0")
+	require.ErrorContains(t, checkWasmInstantiatePermission("v0.28.0", "AnyOfAddresses"), "requires wasmd v0.29.0")
+	require.NoError(t, checkWasmInstantiatePermission("v0.45.0", "AnyOfAddresses"))
+	require.ErrorContains(t, checkWasmInstantiatePermission("v0.45.0", "Somebody"), "unknown instantiate permission")
+}
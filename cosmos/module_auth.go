@@ -0,0 +1,55 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// QueryAccountPubKey returns the public key of the account at address, decoded through the chain's
+// interface registry, so its concrete type shows the key algorithm, e.g. *secp256k1.PubKey.
+// Accounts only have a public key on chain once they signed a transaction.
+func (node *Node) QueryAccountPubKey(ctx context.Context, address string) (cryptotypes.PubKey, error) {
+	account, err := node.queryAccount(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := account.GetPubKey()
+	if pubKey == nil {
+		return nil, fmt.Errorf("account %s has no public key on chain yet", address)
+	}
+	return pubKey, nil
+}
+
+// accountSequence returns the sequence of the next transaction signed by the account at address.
+func (node *Node) accountSequence(ctx context.Context, address string) (uint64, error) {
+	account, err := node.queryAccount(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+	return account.GetSequence(), nil
+}
+
+func (node *Node) queryAccount(ctx context.Context, address string) (types.AccountI, error) {
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	res, err := authtypes.NewQueryClient(conn).Account(ctx, &authtypes.QueryAccountRequest{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account %s: %w", address, err)
+	}
+
+	var account types.AccountI
+	if err := node.Chain.Config().EncodingConfig.InterfaceRegistry.UnpackAny(res.Account, &account); err != nil {
+		return nil, fmt.Errorf("failed to decode account %s: %w", address, err)
+	}
+	return account, nil
+}
@@ -0,0 +1,227 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/decentrio/rollup-e2e-testing/ibc"
+)
+
+// balanceQueryWorkers bounds the number of concurrent balance queries of QueryBalancesMulti.
+const balanceQueryWorkers = 16
+
+// QueryBalancesMulti returns the balance in denom of each of addresses, querying them concurrently over a single
+// gRPC connection. Errors from all failed queries are aggregated in the returned error, along with the balances
+// which could be queried.
+func (node *Node) QueryBalancesMulti(ctx context.Context, addresses []string, denom string) (map[string]math.Int, error) {
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	queryClient := bankTypes.NewQueryClient(conn)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		balances = make(map[string]math.Int, len(addresses))
+		sem      = make(chan struct{}, balanceQueryWorkers)
+	)
+	for _, address := range addresses {
+		address := address
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res, queryErr := queryClient.Balance(ctx, &bankTypes.QueryBalanceRequest{Address: address, Denom: denom})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if queryErr != nil {
+				err = multierr.Append(err, fmt.Errorf("address %s: %w", address, queryErr))
+				return
+			}
+			balances[address] = res.Balance.Amount
+		}()
+	}
+	wg.Wait()
+	return balances, err
+}
+
+// BankSendFrom sends amount from fromAddr to toAddr, signing with keyName.
+// If keyName is not the key of fromAddr, the send is executed through authz, so fromAddr must have granted
+// keyName a send authorization, e.g. with a /cosmos.bank.v1beta1.MsgSend generic authorization.
+func (node *Node) BankSendFrom(ctx context.Context, keyName, fromAddr, toAddr string, amount ibc.WalletAmount) (string, error) {
+	signer, err := node.AccountKeyBech32(ctx, keyName)
+	if err != nil {
+		return "", err
+	}
+	coins := types.NewCoins(types.NewCoin(amount.Denom, amount.Amount))
+	if signer != fromAddr {
+		return node.execViaAuthz(ctx, keyName, &bankTypes.MsgSend{FromAddress: fromAddr, ToAddress: toAddr, Amount: coins})
+	}
+	return node.ExecTx(ctx, keyName, "bank", "send", fromAddr, toAddr, coins.String())
+}
+
+// Gas limit of a multi-send tx built by BankMultiSend, growing with the number of outputs.
+const (
+	multiSendGasBase      = 100_000
+	multiSendGasPerOutput = 30_000
+)
+
+// BankMultiSend sends each of outputs from keyName in a single MsgMultiSend, whose input is the sum of the outputs.
+// The outputs may be in different denoms. The CLI multi-send command sends the same amount to every recipient,
+// so the message is built and broadcast directly. The sender must hold the total of the outputs, fees aside.
+func (node *Node) BankMultiSend(ctx context.Context, keyName string, outputs []ibc.WalletAmount) (string, error) {
+	if len(outputs) == 0 {
+		return "", fmt.Errorf("multi-send needs at least one output")
+	}
+	sender, err := node.AccountKeyBech32(ctx, keyName)
+	if err != nil {
+		return "", err
+	}
+
+	msgOutputs := make([]bankTypes.Output, len(outputs))
+	total := types.NewCoins()
+	for i, output := range outputs {
+		coin := types.NewCoin(output.Denom, output.Amount)
+		if !coin.IsPositive() {
+			return "", fmt.Errorf("output %d to %s: amount must be positive, got %s", i, output.Address, coin)
+		}
+		if output.Address == "" {
+			return "", fmt.Errorf("output %d: empty address", i)
+		}
+		msgOutputs[i] = bankTypes.Output{Address: output.Address, Coins: types.NewCoins(coin)}
+		total = total.Add(coin)
+	}
+	for _, coin := range total {
+		balance, err := node.Chain.GetBalance(ctx, sender, coin.Denom)
+		if err != nil {
+			return "", fmt.Errorf("failed to query balance of %s: %w", sender, err)
+		}
+		if balance.LT(coin.Amount) {
+			return "", fmt.Errorf("sender %s holds %s%s, less than the %s sent in total", sender, balance, coin.Denom, coin)
+		}
+	}
+	input := bankTypes.Input{Address: sender, Coins: total}
+
+	gasLimit := uint64(multiSendGasBase + multiSendGasPerOutput*len(outputs))
+	return node.broadcastMsgs(ctx, keyName, gasLimit, bankTypes.NewMsgMultiSend(input, msgOutputs))
+}
+
+// QueryTotalSupply returns the total supply of every denom of the chain. All result pages are fetched.
+func (node *Node) QueryTotalSupply(ctx context.Context) (types.Coins, error) {
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var supply types.Coins
+	queryClient := bankTypes.NewQueryClient(conn)
+	req := &bankTypes.QueryTotalSupplyRequest{Pagination: &query.PageRequest{}}
+	for {
+		res, err := queryClient.TotalSupply(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		supply = supply.Add(res.Supply...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return supply, nil
+		}
+		req.Pagination.Key = res.Pagination.NextKey
+	}
+}
+
+// QuerySupplyOf returns the total supply of denom.
+func (node *Node) QuerySupplyOf(ctx context.Context, denom string) (types.Coin, error) {
+	stdout, _, err := node.ExecQuery(ctx, "bank", "total", "--denom", denom)
+	// SDK v50 replaced the --denom flag with its own query command.
+	if err != nil && strings.Contains(err.Error(), "unknown flag") {
+		stdout, _, err = node.ExecQuery(ctx, "bank", "total-of", denom)
+	}
+	if err != nil {
+		return types.Coin{}, err
+	}
+
+	// SDK v50 wraps the coin in the query response, older SDKs print the coin itself.
+	var res struct {
+		Amount *types.Coin `json:"amount"`
+	}
+	if err := json.Unmarshal(stdout, &res); err == nil && res.Amount != nil {
+		return *res.Amount, nil
+	}
+
+	var coin types.Coin
+	if err := json.Unmarshal(stdout, &coin); err != nil {
+		return types.Coin{}, err
+	}
+	return coin, nil
+}
+
+// AssertBalancesPreservedAcrossUpgrade snapshots the balances of addresses in every denom, runs upgrade and returns
+// an error unless every balance is identical afterwards. Mismatches of all addresses are aggregated.
+func (node *Node) AssertBalancesPreservedAcrossUpgrade(ctx context.Context, addresses []string, upgrade func(ctx context.Context) error) error {
+	before := make(map[string]types.Coins, len(addresses))
+	for _, address := range addresses {
+		balances, err := node.queryAllBalances(ctx, address)
+		if err != nil {
+			return fmt.Errorf("failed to query balances of %s before upgrade: %w", address, err)
+		}
+		before[address] = balances
+	}
+
+	if err := upgrade(ctx); err != nil {
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	var err error
+	for _, address := range addresses {
+		after, queryErr := node.queryAllBalances(ctx, address)
+		if queryErr != nil {
+			return fmt.Errorf("failed to query balances of %s after upgrade: %w", address, queryErr)
+		}
+		if !after.Equal(before[address]) {
+			err = multierr.Append(err, fmt.Errorf("balances of %s changed across upgrade from %s to %s", address, before[address], after))
+		}
+	}
+	return err
+}
+
+// queryAllBalances returns the balances of address in every denom.
+func (node *Node) queryAllBalances(ctx context.Context, address string) (types.Coins, error) {
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var balances types.Coins
+	queryClient := bankTypes.NewQueryClient(conn)
+	req := &bankTypes.QueryAllBalancesRequest{Address: address, Pagination: &query.PageRequest{}}
+	for {
+		res, err := queryClient.AllBalances(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		balances = balances.Add(res.Balances...)
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return balances, nil
+		}
+		req.Pagination.Key = res.Pagination.NextKey
+	}
+}
@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// pagedSupplyServer serves the total supply one coin per page, keyed by the raw index of the next coin.
+type pagedSupplyServer struct {
+	bankTypes.UnimplementedQueryServer
+	supply types.Coins
+}
+
+func (s *pagedSupplyServer) TotalSupply(_ context.Context, req *bankTypes.QueryTotalSupplyRequest) (*bankTypes.QueryTotalSupplyResponse, error) {
+	i := 0
+	if req.Pagination != nil && len(req.Pagination.Key) > 0 {
+		var err error
+		if i, err = strconv.Atoi(string(req.Pagination.Key)); err != nil {
+			return nil, err
+		}
+	}
+	res := &bankTypes.QueryTotalSupplyResponse{Supply: types.NewCoins(s.supply[i]), Pagination: &query.PageResponse{}}
+	if i+1 < len(s.supply) {
+		res.Pagination.NextKey = []byte(strconv.Itoa(i + 1))
+	}
+	return res, nil
+}
+
+func TestQueryTotalSupplyPaginates(t *testing.T) {
+	t.Parallel()
+
+	supply := types.NewCoins(
+		types.NewCoin("adym", math.NewInt(1)),
+		types.NewCoin("stake", math.NewInt(2)),
+		types.NewCoin("urax", math.NewInt(3)),
+	)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	bankTypes.RegisterQueryServer(server, &pagedSupplyServer{supply: supply})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	node := &Node{hostGRPCPort: lis.Addr().String()}
+	got, err := node.QueryTotalSupply(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, supply, got)
+}
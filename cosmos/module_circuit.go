@@ -0,0 +1,42 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// TripCircuit disables the given message types chain-wide through the x/circuit module.
+// keyName must belong to an account with circuit breaker permissions.
+func (node *Node) TripCircuit(ctx context.Context, keyName string, msgTypeURLs []string) (string, error) {
+	if len(msgTypeURLs) == 0 {
+		return "", errors.New("at least one message type url is required")
+	}
+	command := append([]string{"circuit", "disable"}, msgTypeURLs...)
+	return node.ExecTx(ctx, keyName, command...)
+}
+
+// ResetCircuit re-enables message types previously disabled through TripCircuit.
+func (node *Node) ResetCircuit(ctx context.Context, keyName string, msgTypeURLs []string) (string, error) {
+	if len(msgTypeURLs) == 0 {
+		return "", errors.New("at least one message type url is required")
+	}
+	command := append([]string{"circuit", "reset"}, msgTypeURLs...)
+	return node.ExecTx(ctx, keyName, command...)
+}
+
+// QueryDisabledMsgs returns the message type urls currently disabled by the x/circuit module.
+func (node *Node) QueryDisabledMsgs(ctx context.Context) ([]string, error) {
+	stdout, _, err := node.ExecQuery(ctx, "circuit", "disabled-list")
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		DisabledList []string `json:"disabled_list"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return res.DisabledList, nil
+}
@@ -0,0 +1,50 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/types"
+)
+
+// WithdrawRewards withdraws the rewards of the delegation of keyName to the validator with the given valoper address.
+func (node *Node) WithdrawRewards(ctx context.Context, keyName, validatorAddr string) (string, error) {
+	return node.ExecTx(ctx, keyName, "distribution", "withdraw-rewards", validatorAddr)
+}
+
+// QueryRewards returns the outstanding rewards of the delegation of delegator to validator,
+// or the total of its rewards from all validators if validator is empty.
+func (node *Node) QueryRewards(ctx context.Context, delegator, validator string) (types.DecCoins, error) {
+	if validator == "" {
+		stdout, _, err := node.ExecQuery(ctx, "distribution", "rewards", delegator)
+		if err != nil {
+			return nil, err
+		}
+
+		var res struct {
+			Total types.DecCoins `json:"total"`
+		}
+		if err := json.Unmarshal(stdout, &res); err != nil {
+			return nil, err
+		}
+		return res.Total, nil
+	}
+
+	// SDK v50 moved the rewards of a single delegation to its own query command.
+	stdout, _, err := node.ExecQuery(ctx, "distribution", "rewards-by-validator", delegator, validator)
+	if err != nil && strings.Contains(err.Error(), "unknown command") {
+		stdout, _, err = node.ExecQuery(ctx, "distribution", "rewards", delegator, validator)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Rewards types.DecCoins `json:"rewards"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return res.Rewards, nil
+}
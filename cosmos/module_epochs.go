@@ -0,0 +1,70 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+// QueryEpochInfos returns the epochs tracked by the x/epochs module.
+func (node *Node) QueryEpochInfos(ctx context.Context) ([]EpochInfo, error) {
+	stdout, _, err := node.ExecQuery(ctx, "epochs", "epoch-infos")
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Epochs []EpochInfo `json:"epochs"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return res.Epochs, nil
+}
+
+// QueryEpochInfo returns the epoch with the given identifier, e.g. "day".
+func (node *Node) QueryEpochInfo(ctx context.Context, identifier string) (*EpochInfo, error) {
+	epochs, err := node.QueryEpochInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, epoch := range epochs {
+		if epoch.Identifier == identifier {
+			return &epoch, nil
+		}
+	}
+	return nil, fmt.Errorf("no epoch with identifier %q", identifier)
+}
+
+// WaitForNextEpoch polls the epoch with the given identifier until it advances and returns the new epoch number.
+func (node *Node) WaitForNextEpoch(ctx context.Context, identifier string, timeout time.Duration) (uint64, error) {
+	epoch, err := node.QueryEpochInfo(ctx, identifier)
+	if err != nil {
+		return 0, err
+	}
+	start, err := strconv.ParseUint(epoch.CurrentEpoch, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid current epoch %q: %w", epoch.CurrentEpoch, err)
+	}
+
+	current := start
+	err = testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		epoch, err := node.QueryEpochInfo(ctx, identifier)
+		if err != nil {
+			return false, err
+		}
+		current, err = strconv.ParseUint(epoch.CurrentEpoch, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid current epoch %q: %w", epoch.CurrentEpoch, err)
+		}
+		return current > start, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("epoch %q did not advance from %d: %w", identifier, start, err)
+	}
+	return current, nil
+}
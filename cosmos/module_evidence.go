@@ -0,0 +1,68 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+
+	evidenceexported "cosmossdk.io/x/evidence/exported"
+	evidencetypes "cosmossdk.io/x/evidence/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// QueryAllEvidence returns all evidence of misbehaviour recorded by the x/evidence module, e.g. equivocations
+// of double signing validators. The evidence types must be registered in the chain's encoding config.
+func (node *Node) QueryAllEvidence(ctx context.Context) ([]evidenceexported.Evidence, error) {
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var evidence []evidenceexported.Evidence
+	queryClient := evidencetypes.NewQueryClient(conn)
+	req := &evidencetypes.QueryAllEvidenceRequest{Pagination: &query.PageRequest{}}
+	for {
+		res, err := queryClient.AllEvidence(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, evAny := range res.Evidence {
+			ev, err := node.unpackEvidence(evAny)
+			if err != nil {
+				return nil, err
+			}
+			evidence = append(evidence, ev)
+		}
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return evidence, nil
+		}
+		req.Pagination.Key = res.Pagination.NextKey
+	}
+}
+
+// QueryEvidence returns the evidence with the given hex encoded hash.
+func (node *Node) QueryEvidence(ctx context.Context, hash string) (evidenceexported.Evidence, error) {
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	res, err := evidencetypes.NewQueryClient(conn).Evidence(ctx, &evidencetypes.QueryEvidenceRequest{Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	return node.unpackEvidence(res.Evidence)
+}
+
+// unpackEvidence decodes evidence through the chain's interface registry.
+func (node *Node) unpackEvidence(evAny *codectypes.Any) (evidenceexported.Evidence, error) {
+	var ev evidenceexported.Evidence
+	if err := node.Chain.Config().EncodingConfig.InterfaceRegistry.UnpackAny(evAny, &ev); err != nil {
+		return nil, fmt.Errorf("failed to decode evidence of type %s: %w", evAny.GetTypeUrl(), err)
+	}
+	return ev, nil
+}
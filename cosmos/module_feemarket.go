@@ -0,0 +1,115 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"cosmossdk.io/math"
+
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+const (
+	BaseFeeUp   = "up"
+	BaseFeeDown = "down"
+
+	// baseFeeLoadGas is the gas wanted by each tx AssertBaseFeeAdjusts sends to fill blocks.
+	baseFeeLoadGas = 5_000_000
+)
+
+// QueryFeeMarketBaseFee returns the current EIP-1559 base fee of the x/feemarket module.
+func (node *Node) QueryFeeMarketBaseFee(ctx context.Context) (math.LegacyDec, error) {
+	stdout, _, err := node.ExecQuery(ctx, "feemarket", "base-fee")
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	var res struct {
+		BaseFee string `json:"base_fee"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return math.LegacyDec{}, err
+	}
+	return math.LegacyNewDecFromStr(res.BaseFee)
+}
+
+// AssertBaseFeeAdjusts checks the x/feemarket base fee moves in direction, BaseFeeUp or BaseFeeDown, over blocks.
+// For BaseFeeUp keyName keeps sending gas heavy self transfers so blocks exceed the gas target,
+// for BaseFeeDown no load is generated so blocks stay below it.
+func (node *Node) AssertBaseFeeAdjusts(ctx context.Context, keyName string, direction string, blocks int) error {
+	if direction != BaseFeeUp && direction != BaseFeeDown {
+		return fmt.Errorf("direction must be %q or %q, got %q", BaseFeeUp, BaseFeeDown, direction)
+	}
+	if blocks < 1 {
+		return fmt.Errorf("blocks must be positive, got %d", blocks)
+	}
+
+	startFee, err := node.QueryFeeMarketBaseFee(ctx)
+	if err != nil {
+		return err
+	}
+
+	if direction == BaseFeeDown {
+		if err := testutil.WaitForBlocks(ctx, blocks, node); err != nil {
+			return err
+		}
+	} else if err := node.fillBlocks(ctx, keyName, blocks); err != nil {
+		return err
+	}
+
+	endFee, err := node.QueryFeeMarketBaseFee(ctx)
+	if err != nil {
+		return err
+	}
+	if (direction == BaseFeeUp && !endFee.GT(startFee)) || (direction == BaseFeeDown && !endFee.LT(startFee)) {
+		return fmt.Errorf("base fee did not go %s over %d blocks: %s -> %s", direction, blocks, startFee, endFee)
+	}
+	return nil
+}
+
+// fillBlocks broadcasts gas heavy self transfers from keyName, without waiting for inclusion, until blocks passed.
+func (node *Node) fillBlocks(ctx context.Context, keyName string, blocks int) error {
+	addr, err := node.AccountKeyBech32(ctx, keyName)
+	if err != nil {
+		return err
+	}
+	sequence, err := node.accountSequence(ctx, addr)
+	if err != nil {
+		return err
+	}
+	startHeight, err := node.Height(ctx)
+	if err != nil {
+		return err
+	}
+
+	for height := startHeight; height < startHeight+uint64(blocks); {
+		// Pay well above the base fee so the load is not rejected as the fee rises.
+		baseFee, err := node.QueryFeeMarketBaseFee(ctx)
+		if err != nil {
+			return err
+		}
+		gasPrices := baseFee.MulInt64(2).String() + node.Chain.Config().Denom
+
+		stdout, _, err := node.execTxCommand(ctx, keyName, "bank", "send", addr, addr, "1"+node.Chain.Config().Denom,
+			"--gas", strconv.Itoa(baseFeeLoadGas), "--gas-prices", gasPrices,
+			"--sequence", strconv.FormatUint(sequence, 10))
+		if err != nil {
+			return err
+		}
+		output := CosmosTx{}
+		if err := json.Unmarshal(stdout, &output); err != nil {
+			return err
+		}
+		if output.Code != 0 {
+			return fmt.Errorf("load transaction failed with code %d: %s", output.Code, output.RawLog)
+		}
+		sequence++
+
+		if height, err = node.Height(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
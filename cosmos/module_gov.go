@@ -0,0 +1,350 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	govv1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	paramsutils "github.com/cosmos/cosmos-sdk/x/params/client/utils"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/decentrio/rollup-e2e-testing/ibc"
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+// QueryProposals returns all governance proposals with the given status, e.g. ProposalStatusVotingPeriod.
+// An empty status returns proposals in any status. All result pages are fetched.
+func (node *Node) QueryProposals(ctx context.Context, status string) ([]ProposalResponse, error) {
+	req := &govv1.QueryProposalsRequest{Pagination: &query.PageRequest{}}
+	if status != "" {
+		value, ok := govv1.ProposalStatus_value[status]
+		if !ok {
+			return nil, fmt.Errorf("unknown proposal status %q", status)
+		}
+		req.ProposalStatus = govv1.ProposalStatus(value)
+	}
+
+	conn, err := grpc.Dial(node.hostGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var proposals []ProposalResponse
+	queryClient := govv1.NewQueryClient(conn)
+	for {
+		res, err := queryClient.Proposals(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range res.Proposals {
+			proposals = append(proposals, proposalResponse(p))
+		}
+		if res.Pagination == nil || len(res.Pagination.NextKey) == 0 {
+			return proposals, nil
+		}
+		req.Pagination.Key = res.Pagination.NextKey
+	}
+}
+
+// proposalResponse converts a gov v1 proposal to the shape of the proposal query CLI output.
+func proposalResponse(p *govv1.Proposal) ProposalResponse {
+	res := ProposalResponse{
+		ProposalID: strconv.FormatUint(p.Id, 10),
+		Content:    ProposalContent{Title: p.Title, Description: p.Summary},
+		Status:     p.Status.String(),
+	}
+	if len(p.Messages) > 0 {
+		res.Content.Type = p.Messages[0].TypeUrl
+	}
+	if tally := p.FinalTallyResult; tally != nil {
+		res.FinalTallyResult = ProposalFinalTallyResult{
+			Yes:        tally.YesCount,
+			Abstain:    tally.AbstainCount,
+			No:         tally.NoCount,
+			NoWithVeto: tally.NoWithVetoCount,
+		}
+	}
+	for _, coin := range p.TotalDeposit {
+		res.TotalDeposit = append(res.TotalDeposit, ProposalDeposit{Denom: coin.Denom, Amount: coin.Amount.String()})
+	}
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+	res.SubmitTime = formatTime(p.SubmitTime)
+	res.DepositEndTime = formatTime(p.DepositEndTime)
+	res.VotingStartTime = formatTime(p.VotingStartTime)
+	res.VotingEndTime = formatTime(p.VotingEndTime)
+	return res
+}
+
+// VoteAll has every validator node vote on the proposal with its validator key, concurrently.
+// Full nodes are skipped. Errors from all failed votes are aggregated in the returned error.
+func (nodes Nodes) VoteAll(ctx context.Context, proposalID, vote string) error {
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		err error
+	)
+	for _, n := range nodes {
+		if !n.Validator {
+			continue
+		}
+		n := n
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if voteErr := n.VoteOnProposal(ctx, valKey, proposalID, vote); voteErr != nil {
+				mu.Lock()
+				err = multierr.Append(err, fmt.Errorf("validator %s: %w", n.Name(), voteErr))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return err
+}
+
+// GovExecViaAuthz broadcasts govMsg wrapped in an authz MsgExec signed by granteeKey.
+// The signer of govMsg is the granter, which must have granted the grantee authorization for the message type.
+// The message type must be registered in the chain's encoding config.
+func (node *Node) GovExecViaAuthz(ctx context.Context, granteeKey string, govMsg types.Msg) (string, error) {
+	return node.execViaAuthz(ctx, granteeKey, govMsg)
+}
+
+// execViaAuthz broadcasts msg wrapped in an authz MsgExec signed by granteeKey.
+func (node *Node) execViaAuthz(ctx context.Context, granteeKey string, msg types.Msg) (string, error) {
+	txConfig := node.Chain.Config().EncodingConfig.TxConfig
+	txBuilder := txConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		return "", fmt.Errorf("failed to build inner tx: %w", err)
+	}
+	bz, err := txConfig.TxJSONEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return "", fmt.Errorf("failed to encode inner tx: %w", err)
+	}
+
+	file := tempJSONFileName("authz-exec")
+	defer node.removeFiles(ctx, file)
+	if err := node.WriteFile(ctx, bz, file); err != nil {
+		return "", fmt.Errorf("writing authz exec file to docker volume: %w", err)
+	}
+
+	return node.ExecTx(ctx, granteeKey, "authz", "exec", path.Join(node.HomeDir(), file), "--gas", "auto")
+}
+
+// QueryProposalTally returns the current tally of a proposal, or its final tally once voting ended.
+// It understands both the gov v1 and the legacy gov tally formats.
+func (node *Node) QueryProposalTally(ctx context.Context, proposalID string) (*TallyResult, error) {
+	stdout, _, err := node.ExecQuery(ctx, "gov", "tally", proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// SDK v50 wraps the tally in the query response, older SDKs print the tally itself.
+	var res struct {
+		Tally *rawTallyResult `json:"tally"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	raw := res.Tally
+	if raw == nil {
+		raw = &rawTallyResult{}
+		if err := json.Unmarshal(stdout, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	// gov v1 suffixes the counts with _count, the legacy gov format does not.
+	counts := []string{raw.YesCount, raw.NoCount, raw.AbstainCount, raw.NoWithVetoCount}
+	if strings.Join(counts, "") == "" {
+		counts = []string{raw.Yes, raw.No, raw.Abstain, raw.NoWithVeto}
+	}
+	parsed := make([]math.Int, len(counts))
+	for i, count := range counts {
+		if count == "" {
+			parsed[i] = math.ZeroInt()
+			continue
+		}
+		n, ok := math.NewIntFromString(count)
+		if !ok {
+			return nil, fmt.Errorf("invalid tally count %q", count)
+		}
+		parsed[i] = n
+	}
+	return &TallyResult{Yes: parsed[0], No: parsed[1], Abstain: parsed[2], NoWithVeto: parsed[3]}, nil
+}
+
+// rawTallyResult holds the counts of a tally query response in either the gov v1 or the legacy format.
+type rawTallyResult struct {
+	YesCount        string `json:"yes_count"`
+	NoCount         string `json:"no_count"`
+	AbstainCount    string `json:"abstain_count"`
+	NoWithVetoCount string `json:"no_with_veto_count"`
+
+	Yes        string `json:"yes"`
+	No         string `json:"no"`
+	Abstain    string `json:"abstain"`
+	NoWithVeto string `json:"no_with_veto"`
+}
+
+// ReachedQuorum reports whether the votes cast on a proposal reach the gov quorum of the currently bonded tokens.
+// This distinguishes proposals rejected for low turnout from proposals rejected by vote.
+func (node *Node) ReachedQuorum(ctx context.Context, proposalID string) (bool, error) {
+	tally, err := node.QueryProposalTally(ctx, proposalID)
+	if err != nil {
+		return false, err
+	}
+
+	voted := tally.Total()
+
+	pool, err := node.QueryStakingPool(ctx)
+	if err != nil {
+		return false, err
+	}
+	quorum, err := node.queryGovQuorum(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if pool.BondedTokens.IsZero() {
+		return false, nil
+	}
+	return math.LegacyNewDecFromInt(voted).QuoInt(pool.BondedTokens).GTE(quorum), nil
+}
+
+// queryGovQuorum returns the fraction of bonded tokens which must vote for a proposal result to be valid.
+func (node *Node) queryGovQuorum(ctx context.Context) (math.LegacyDec, error) {
+	stdout, _, err := node.ExecQuery(ctx, "gov", "params")
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	// SDK v47+ returns the quorum in params, older SDKs in tally_params.
+	var res struct {
+		Params struct {
+			Quorum string `json:"quorum"`
+		} `json:"params"`
+		TallyParams struct {
+			Quorum string `json:"quorum"`
+		} `json:"tally_params"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return math.LegacyDec{}, err
+	}
+	quorum := res.Params.Quorum
+	if quorum == "" {
+		quorum = res.TallyParams.Quorum
+	}
+	return math.LegacyNewDecFromStr(quorum)
+}
+
+// DepositOnProposal deposits amount from keyName on a proposal in its deposit period.
+func (node *Node) DepositOnProposal(ctx context.Context, keyName, proposalID string, amount ibc.WalletAmount) (string, error) {
+	coin := types.NewCoin(amount.Denom, amount.Amount)
+	return node.ExecTx(ctx, keyName, "gov", "deposit", proposalID, coin.String())
+}
+
+// QueryProposalDeposits returns the deposits made on a proposal.
+// Deposits are pruned once a proposal leaves the voting period.
+func (node *Node) QueryProposalDeposits(ctx context.Context, proposalID string) ([]Deposit, error) {
+	stdout, _, err := node.ExecQuery(ctx, "gov", "deposits", proposalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Deposits []Deposit `json:"deposits"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return res.Deposits, nil
+}
+
+// paramChangeProposalTimeout bounds the wait for a param change proposal submitted by AssertParamChangeRejected
+// to leave its voting period.
+const paramChangeProposalTimeout = 5 * time.Minute
+
+// AssertParamChangeRejected submits the param change proposal and returns an error unless the change is rejected,
+// either when validating the proposal at submission or when applying it after it passed a unanimous yes vote of
+// the validators. Which of the two rejected it is logged; the returned error tells apart the ways the assertion
+// fails: the change applied, or the proposal was rejected by vote and never reached the apply step.
+func (node *Node) AssertParamChangeRejected(ctx context.Context, keyName string, prop *paramsutils.ParamChangeProposalJSON) error {
+	chain, ok := node.Chain.(*CosmosChain)
+	if !ok {
+		return fmt.Errorf("chain %s is not a cosmos chain", node.Chain.Config().ChainID)
+	}
+
+	before := make([]*ParamChange, len(prop.Changes))
+	for i, change := range prop.Changes {
+		param, err := node.QueryParam(ctx, change.Subspace, change.Key)
+		if err != nil {
+			return fmt.Errorf("failed to query param %s/%s: %w", change.Subspace, change.Key, err)
+		}
+		before[i] = param
+	}
+
+	txHash, err := node.ParamChangeProposal(ctx, keyName, prop)
+	if err != nil {
+		node.logger().Info("Param change rejected at submission", zap.Error(err))
+		return nil
+	}
+	txResp, err := node.GetTransaction(node.CliContext(), txHash)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+	proposalID, ok := AttributeValue(txResp.Events, "submit_proposal", "proposal_id")
+	if !ok {
+		return fmt.Errorf("tx %s did not submit a proposal", txHash)
+	}
+
+	if err := chain.Nodes().VoteAll(ctx, proposalID, "yes"); err != nil {
+		return fmt.Errorf("failed to vote on proposal %s: %w", proposalID, err)
+	}
+	var status string
+	err = testutil.WaitForCondition(paramChangeProposalTimeout, blockTime*time.Second, func() (bool, error) {
+		proposal, err := node.QueryProposal(ctx, proposalID)
+		if err != nil {
+			return false, err
+		}
+		status = proposal.Status
+		return status != ProposalStatusDepositPeriod && status != ProposalStatusVotingPeriod, nil
+	})
+	if err != nil {
+		return fmt.Errorf("proposal %s did not end its voting period: %w", proposalID, err)
+	}
+	if status == ProposalStatusRejected {
+		return fmt.Errorf("proposal %s was rejected by vote, the param change was never applied nor validated", proposalID)
+	}
+
+	for i, change := range prop.Changes {
+		after, err := node.QueryParam(ctx, change.Subspace, change.Key)
+		if err != nil {
+			return fmt.Errorf("failed to query param %s/%s: %w", change.Subspace, change.Key, err)
+		}
+		if !reflect.DeepEqual(after, before[i]) {
+			return fmt.Errorf("proposal %s ended with status %s and changed param %s/%s from %v to %v, expected the change to be rejected",
+				proposalID, status, change.Subspace, change.Key, before[i].Value, after.Value)
+		}
+	}
+	node.logger().Info("Param change rejected when applied", zap.String("proposal_id", proposalID), zap.String("status", status))
+	return nil
+}
@@ -0,0 +1,115 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosmossdk.io/math"
+)
+
+// QueryInflation returns the current annual inflation rate of the x/mint module.
+func (node *Node) QueryInflation(ctx context.Context) (math.LegacyDec, error) {
+	stdout, _, err := node.ExecQuery(ctx, "mint", "inflation")
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+
+	// SDK v50 wraps the inflation in the query response, older SDKs print the bare decimal.
+	inflation := strings.Trim(strings.TrimSpace(string(stdout)), `"`)
+	var res struct {
+		Inflation string `json:"inflation"`
+	}
+	if err := json.Unmarshal(stdout, &res); err == nil && res.Inflation != "" {
+		inflation = res.Inflation
+		// SDK v50 autocli prints the bytes of the decimal base64 encoded.
+		if decoded, err := base64.StdEncoding.DecodeString(inflation); err == nil {
+			if dec, err := math.LegacyNewDecFromStr(string(decoded)); err == nil {
+				return dec, nil
+			}
+		}
+	}
+	return math.LegacyNewDecFromStr(inflation)
+}
+
+// epochWaitMargin is added to the epoch duration to bound the wait for each epoch in AssertInflationOverEpochs.
+const epochWaitMargin = time.Minute
+
+// AssertInflationOverEpochs waits for the given number of epochs to pass and returns an error unless the inflation
+// stays within expectedRange, bounds included, at the start of each epoch.
+// The epoch followed is the mint epoch of the chain if its mint params have one, the shortest epoch otherwise.
+func (node *Node) AssertInflationOverEpochs(ctx context.Context, epochs int, expectedRange [2]math.LegacyDec) error {
+	epoch, err := node.mintEpoch(ctx)
+	if err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(epoch.Duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q of epoch %q: %w", epoch.Duration, epoch.Identifier, err)
+	}
+
+	for i := 0; i < epochs; i++ {
+		current, err := node.WaitForNextEpoch(ctx, epoch.Identifier, duration+epochWaitMargin)
+		if err != nil {
+			return err
+		}
+		inflation, err := node.QueryInflation(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query inflation in epoch %d: %w", current, err)
+		}
+		if inflation.LT(expectedRange[0]) || inflation.GT(expectedRange[1]) {
+			return fmt.Errorf("inflation %s in %q epoch %d is outside of [%s, %s]",
+				inflation, epoch.Identifier, current, expectedRange[0], expectedRange[1])
+		}
+	}
+	return nil
+}
+
+// mintEpoch returns the epoch the chain mints by, falling back to its shortest epoch.
+func (node *Node) mintEpoch(ctx context.Context) (*EpochInfo, error) {
+	stdout, _, err := node.ExecQuery(ctx, "mint", "params")
+	if err != nil {
+		return nil, err
+	}
+	var res struct {
+		Params struct {
+			EpochIdentifier string `json:"epoch_identifier"`
+		} `json:"params"`
+		EpochIdentifier string `json:"epoch_identifier"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	identifier := res.Params.EpochIdentifier
+	if identifier == "" {
+		identifier = res.EpochIdentifier
+	}
+	if identifier != "" {
+		return node.QueryEpochInfo(ctx, identifier)
+	}
+
+	epochs, err := node.QueryEpochInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		shortest    *EpochInfo
+		shortestDur time.Duration
+	)
+	for i, epoch := range epochs {
+		duration, err := time.ParseDuration(epoch.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q of epoch %q: %w", epoch.Duration, epoch.Identifier, err)
+		}
+		if shortest == nil || duration < shortestDur {
+			shortest, shortestDur = &epochs[i], duration
+		}
+	}
+	if shortest == nil {
+		return nil, fmt.Errorf("chain %s has no epochs", node.Chain.Config().ChainID)
+	}
+	return shortest, nil
+}
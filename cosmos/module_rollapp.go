@@ -0,0 +1,266 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	transfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+	"go.uber.org/multierr"
+
+	"github.com/decentrio/rollup-e2e-testing/ibc"
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+// QueryLatestStateInfo returns the latest state update the given rollapp submitted to the hub.
+func (node *Node) QueryLatestStateInfo(ctx context.Context, rollappChainID string) (*StateInfo, error) {
+	stdout, _, err := node.ExecQuery(ctx, "rollapp", "state", rollappChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		StateInfo StateInfo `json:"stateInfo"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return &res.StateInfo, nil
+}
+
+// WaitForFirstStateUpdate polls the hub until the given rollapp submitted at least one state update
+// and returns the index of the latest one.
+func (node *Node) WaitForFirstStateUpdate(ctx context.Context, rollappChainID string, timeout time.Duration) (uint64, error) {
+	var stateIndex uint64
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		info, err := node.QueryLatestStateInfo(ctx, rollappChainID)
+		if err != nil {
+			// The query fails until the first state update exists.
+			if strings.Contains(err.Error(), "not found") {
+				return false, nil
+			}
+			return false, err
+		}
+		if info.StateInfoIndex.Index == "" {
+			return false, nil
+		}
+		stateIndex, err = strconv.ParseUint(info.StateInfoIndex.Index, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid state index %q: %w", info.StateInfoIndex.Index, err)
+		}
+		return stateIndex > 0, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rollapp %s did not submit a state update: %w", rollappChainID, err)
+	}
+	return stateIndex, nil
+}
+
+// finalizedHeight returns the last rollapp height finalized on the hub, 0 if no state update was finalized yet.
+func (node *Node) finalizedHeight(ctx context.Context, rollappChainID string) (uint64, error) {
+	stdout, _, err := node.ExecQuery(ctx, "rollapp", "state", rollappChainID, "--finalized")
+	if err != nil {
+		// The query fails until the first state update is finalized.
+		if strings.Contains(err.Error(), "not found") {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var res struct {
+		StateInfo StateInfo `json:"stateInfo"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return 0, err
+	}
+	if res.StateInfo.StartHeight == "" {
+		return 0, nil
+	}
+	start, err := strconv.ParseUint(res.StateInfo.StartHeight, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start height %q: %w", res.StateInfo.StartHeight, err)
+	}
+	numBlocks, err := strconv.ParseUint(res.StateInfo.NumBlocks, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number of blocks %q: %w", res.StateInfo.NumBlocks, err)
+	}
+	return start + numBlocks - 1, nil
+}
+
+// WaitForRollappFinalization polls the hub until the state update including the given rollapp height is finalized,
+// i.e. its dispute period passed.
+func (node *Node) WaitForRollappFinalization(ctx context.Context, rollappChainID string, height uint64, timeout time.Duration) error {
+	var finalized uint64
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		var err error
+		finalized, err = node.finalizedHeight(ctx, rollappChainID)
+		if err != nil {
+			return false, err
+		}
+		return finalized >= height, nil
+	})
+	if err != nil {
+		return fmt.Errorf("rollapp %s height %d was not finalized, last finalized height %d: %w", rollappChainID, height, finalized, err)
+	}
+	return nil
+}
+
+// pendingFinalizationBlocks is the number of hub blocks AssertTransferBlockedPreFinalization checks a transfer
+// from a rollapp stays uncredited for.
+const pendingFinalizationBlocks = 5
+
+// AssertTransferBlockedPreFinalization sends amount from keyName on the rollapp to amount.Address on the hub over
+// channelID, the rollapp end of its transfer channel to the hub, and returns an error unless the hub holds back the
+// transfer until the rollapp height of the transfer is finalized, then credits it.
+// The dispute period of the hub must outlast pendingFinalizationBlocks, and a relayer must be relaying the channel.
+func (node *Node) AssertTransferBlockedPreFinalization(ctx context.Context, rollapp *Node, channelID, keyName string, amount ibc.WalletAmount, timeout time.Duration) error {
+	rollappChainID := rollapp.Chain.Config().ChainID
+	hubChannelID, err := node.transferChannelTo(ctx, rollappChainID)
+	if err != nil {
+		return err
+	}
+	voucher := transfertypes.ParseDenomTrace(transfertypes.GetPrefixedDenom(transfertypes.PortID, hubChannelID, amount.Denom)).IBCDenom()
+
+	start, err := node.Chain.GetBalance(ctx, amount.Address, voucher)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := rollapp.SendIBCTransfer(ctx, channelID, keyName, amount, ibc.TransferOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to send %s%s to the hub: %w", amount.Amount, amount.Denom, err)
+	}
+	txResp, err := rollapp.GetTransaction(rollapp.CliContext(), txHash)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+	transferHeight := uint64(txResp.Height)
+
+	if err := testutil.WaitForBlocks(ctx, pendingFinalizationBlocks, node); err != nil {
+		return err
+	}
+	balance, err := node.Chain.GetBalance(ctx, amount.Address, voucher)
+	if err != nil {
+		return err
+	}
+	finalized, err := node.finalizedHeight(ctx, rollappChainID)
+	if err != nil {
+		return err
+	}
+	if finalized >= transferHeight {
+		return fmt.Errorf("rollapp %s height %d of the transfer was finalized within %d blocks, the dispute period is too short to assert the transfer is held back",
+			rollappChainID, transferHeight, pendingFinalizationBlocks)
+	}
+	if !balance.Equal(start) {
+		return fmt.Errorf("transfer from non-finalized rollapp %s height %d was credited: balance of %s went from %s%s to %s%s",
+			rollappChainID, transferHeight, amount.Address, start, voucher, balance, voucher)
+	}
+
+	if err := node.WaitForRollappFinalization(ctx, rollappChainID, transferHeight, timeout); err != nil {
+		return err
+	}
+	err = testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		balance, err = node.Chain.GetBalance(ctx, amount.Address, voucher)
+		if err != nil {
+			return false, nil
+		}
+		return balance.GT(start), nil
+	})
+	if err != nil {
+		return fmt.Errorf("transfer from rollapp %s height %d was not credited after finalization, balance of %s is %s%s: %w",
+			rollappChainID, transferHeight, amount.Address, balance, voucher, err)
+	}
+	return nil
+}
+
+// RegisterMultipleRollapps registers each of rollapps on the hub along with its sequencer, signing with keyName.
+// Chain IDs must be unique. A rollapp failing to register does not stop the others; the errors of all failed
+// rollapps are aggregated in the returned error.
+func RegisterMultipleRollapps(ctx context.Context, hub *Node, keyName string, rollapps []RollappRegistration) error {
+	seen := make(map[string]struct{}, len(rollapps))
+	for _, rollapp := range rollapps {
+		if rollapp.ChainID == "" {
+			return fmt.Errorf("rollapp with empty chain id")
+		}
+		if _, ok := seen[rollapp.ChainID]; ok {
+			return fmt.Errorf("duplicate rollapp chain id %s", rollapp.ChainID)
+		}
+		seen[rollapp.ChainID] = struct{}{}
+	}
+
+	// Registrations are sent one at a time since they are all signed by keyName.
+	var err error
+	for _, rollapp := range rollapps {
+		if regErr := hub.RegisterRollAppToHub(ctx, keyName, rollapp.ChainID, rollapp.MaxSequencers, rollapp.SequencerAddr); regErr != nil {
+			err = multierr.Append(err, fmt.Errorf("rollapp %s: failed to register rollapp: %w", rollapp.ChainID, regErr))
+			continue
+		}
+		if regErr := hub.RegisterSequencerToHub(ctx, keyName, rollapp.ChainID, rollapp.MaxSequencers, rollapp.SequencerPubKey); regErr != nil {
+			err = multierr.Append(err, fmt.Errorf("rollapp %s: failed to register sequencer: %w", rollapp.ChainID, regErr))
+		}
+	}
+	return err
+}
+
+// QueryAllRollapps returns all rollapps registered on the hub along with the number of their sequencers.
+// All result pages are fetched.
+func (node *Node) QueryAllRollapps(ctx context.Context) ([]RollappSummary, error) {
+	var (
+		rollapps []RollappSummary
+		pageKey  string
+	)
+	for {
+		command := []string{"rollapp", "list"}
+		if pageKey != "" {
+			command = append(command, "--page-key", pageKey)
+		}
+		stdout, _, err := node.ExecQuery(ctx, command...)
+		if err != nil {
+			return nil, err
+		}
+
+		var res struct {
+			Rollapp []struct {
+				RollappID                 string          `json:"rollappId"`
+				Frozen                    bool            `json:"frozen"`
+				LatestStateIndex          *stateInfoIndex `json:"latestStateIndex"`
+				LatestFinalizedStateIndex *stateInfoIndex `json:"latestFinalizedStateIndex"`
+			} `json:"rollapp"`
+			Pagination PaginationResponse `json:"pagination"`
+		}
+		if err := json.Unmarshal(stdout, &res); err != nil {
+			return nil, err
+		}
+		for _, r := range res.Rollapp {
+			sequencers, err := node.QuerySequencers(ctx, r.RollappID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query sequencers of rollapp %s: %w", r.RollappID, err)
+			}
+			summary := RollappSummary{ChainID: r.RollappID, Frozen: r.Frozen, Sequencers: len(sequencers)}
+			if r.LatestStateIndex != nil {
+				summary.LatestStateIndex = r.LatestStateIndex.Index
+			}
+			if r.LatestFinalizedStateIndex != nil {
+				summary.LatestFinalizedStateIndex = r.LatestFinalizedStateIndex.Index
+			}
+			summary.Status = summary.rollappStatus()
+			rollapps = append(rollapps, summary)
+		}
+
+		if res.Pagination.NextKey == "" {
+			return rollapps, nil
+		}
+		if pageKey, err = res.Pagination.pageKeyFlag(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// stateInfoIndex identifies a state update of a rollapp in hub query responses.
+type stateInfoIndex struct {
+	RollappID string `json:"rollappId"`
+	Index     string `json:"index"`
+}
@@ -0,0 +1,108 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+// QuerySequencers returns the sequencers registered on the hub for the given rollapp.
+func (node *Node) QuerySequencers(ctx context.Context, rollappChainID string) ([]Sequencer, error) {
+	stdout, _, err := node.ExecQuery(ctx, "sequencer", "show-sequencers-by-rollapp", rollappChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Sequencers []Sequencer `json:"sequencers"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return res.Sequencers, nil
+}
+
+// CountBondedSequencers returns the number of bonded sequencers of the given rollapp on the hub.
+func (node *Node) CountBondedSequencers(ctx context.Context, rollappChainID string) (int, error) {
+	sequencers, err := node.QuerySequencers(ctx, rollappChainID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, seq := range sequencers {
+		if seq.Status == SequencerStatusBonded {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CurrentSequencerProposer returns the address of the sequencer currently proposing blocks of the given rollapp.
+func (node *Node) CurrentSequencerProposer(ctx context.Context, rollappChainID string) (string, error) {
+	sequencers, err := node.QuerySequencers(ctx, rollappChainID)
+	if err != nil {
+		return "", err
+	}
+	for _, seq := range sequencers {
+		if seq.Proposer {
+			return seq.SequencerAddress, nil
+		}
+	}
+	return "", fmt.Errorf("rollapp %s has no proposer among its %d sequencers", rollappChainID, len(sequencers))
+}
+
+// WaitForProposerChange polls the proposer of the given rollapp until it differs from baseline and returns it.
+func (node *Node) WaitForProposerChange(ctx context.Context, rollappChainID, baseline string, timeout time.Duration) (string, error) {
+	var proposer string
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		var err error
+		proposer, err = node.CurrentSequencerProposer(ctx, rollappChainID)
+		if err != nil {
+			// The rollapp has no proposer while rotating.
+			return false, nil
+		}
+		return proposer != baseline, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("proposer of rollapp %s did not change from %s: %w", rollappChainID, baseline, err)
+	}
+	return proposer, nil
+}
+
+// QuerySequencer returns the sequencer registered on the hub with the given address.
+func (node *Node) QuerySequencer(ctx context.Context, seqAddr string) (*Sequencer, error) {
+	stdout, _, err := node.ExecQuery(ctx, "sequencer", "show-sequencer", seqAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Sequencer Sequencer `json:"sequencer"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return &res.Sequencer, nil
+}
+
+// WaitForSequencerJailed polls the sequencer until the hub jails it, e.g. after its rollapp was stopped and
+// the sequencer missed its batch submissions.
+func (node *Node) WaitForSequencerJailed(ctx context.Context, seqAddr string, timeout time.Duration) error {
+	var status string
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		seq, err := node.QuerySequencer(ctx, seqAddr)
+		if err != nil {
+			return false, err
+		}
+		status = seq.Status
+		return seq.Jailed, nil
+	})
+	if err != nil {
+		return fmt.Errorf("sequencer %s was not jailed, last status %s: %w", seqAddr, status, err)
+	}
+	return nil
+}
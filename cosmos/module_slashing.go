@@ -0,0 +1,54 @@
+package cosmos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	abcitypes "github.com/cometbft/cometbft/abci/types"
+)
+
+// FindSlashEvents scans the finalize block events from startHeight to endHeight (inclusive)
+// and returns every slash event found, e.g. for a validator jailed for downtime.
+func (node *Node) FindSlashEvents(ctx context.Context, startHeight, endHeight uint64) ([]SlashEvent, error) {
+	if endHeight < startHeight {
+		return nil, fmt.Errorf("end height %d is lower than start height %d", endHeight, startHeight)
+	}
+
+	var slashes []SlashEvent
+	for height := startHeight; height <= endHeight; height++ {
+		h := int64(height)
+		res, err := node.Client.BlockResults(ctx, &h)
+		if err != nil {
+			return nil, fmt.Errorf("tendermint rpc get block results at height %d: %w", height, err)
+		}
+		for _, e := range res.FinalizeBlockEvents {
+			if e.Type != "slash" {
+				continue
+			}
+			slash, err := parseSlashEvent(height, e)
+			if err != nil {
+				return nil, err
+			}
+			slashes = append(slashes, slash)
+		}
+	}
+	return slashes, nil
+}
+
+func parseSlashEvent(height uint64, e abcitypes.Event) (SlashEvent, error) {
+	events := []abcitypes.Event{e}
+	slash := SlashEvent{Height: height}
+	slash.Address, _ = AttributeValue(events, e.Type, "address")
+	slash.Reason, _ = AttributeValue(events, e.Type, "reason")
+	slash.Jailed, _ = AttributeValue(events, e.Type, "jailed")
+
+	if power, ok := AttributeValue(events, e.Type, "power"); ok {
+		p, err := strconv.ParseInt(power, 10, 64)
+		if err != nil {
+			return slash, fmt.Errorf("invalid slash power %q at height %d: %w", power, height, err)
+		}
+		slash.Power = p
+	}
+	return slash, nil
+}
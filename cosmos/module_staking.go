@@ -0,0 +1,186 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/decentrio/rollup-e2e-testing/ibc"
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+// QueryStakingPool returns the amount of bonded and not bonded tokens held by the staking module.
+func (node *Node) QueryStakingPool(ctx context.Context) (*stakingtypes.Pool, error) {
+	stdout, _, err := node.ExecQuery(ctx, "staking", "pool")
+	if err != nil {
+		return nil, err
+	}
+
+	// SDK v50 wraps the pool in the query response, older SDKs print the pool itself.
+	var res struct {
+		Pool *stakingtypes.Pool `json:"pool"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	if res.Pool != nil {
+		return res.Pool, nil
+	}
+
+	var pool stakingtypes.Pool
+	if err := json.Unmarshal(stdout, &pool); err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// WaitForUnbondingComplete polls the unbonding delegation of delegator from validator until no entries remain,
+// i.e. the unbonding period has elapsed and the tokens were returned to the delegator.
+func (node *Node) WaitForUnbondingComplete(ctx context.Context, delegator, validator string, timeout time.Duration) error {
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		ubd, err := node.queryUnbondingDelegation(ctx, delegator, validator)
+		if err != nil {
+			return false, err
+		}
+		return ubd == nil || len(ubd.Entries) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("unbonding of %s from %s did not complete: %w", delegator, validator, err)
+	}
+	return nil
+}
+
+// queryUnbondingDelegation returns the unbonding delegation of delegator from validator,
+// or nil if there is none.
+func (node *Node) queryUnbondingDelegation(ctx context.Context, delegator, validator string) (*UnbondingDelegation, error) {
+	stdout, _, err := node.ExecQuery(ctx, "staking", "unbonding-delegation", delegator, validator)
+	if err != nil {
+		// The entry is removed once all of its unbondings have matured.
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// SDK v50 wraps the unbonding delegation in the query response, older SDKs print it directly.
+	var res struct {
+		Unbond *UnbondingDelegation `json:"unbond"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	if res.Unbond != nil {
+		return res.Unbond, nil
+	}
+
+	var ubd UnbondingDelegation
+	if err := json.Unmarshal(stdout, &ubd); err != nil {
+		return nil, err
+	}
+	return &ubd, nil
+}
+
+// CreateValidator creates a new validator operated by keyName with a freshly generated consensus key,
+// self delegating selfDelegation at the given commission rate, e.g. "0.10".
+// The validator does not sign blocks, it is meant for exercising the create-validator path.
+func (node *Node) CreateValidator(ctx context.Context, keyName string, selfDelegation types.Coin, commissionRate string) (string, error) {
+	pubKey := ed25519.GenPrivKey().PubKey()
+	validator := map[string]interface{}{
+		"pubkey": map[string]string{
+			"@type": "/cosmos.crypto.ed25519.PubKey",
+			"key":   base64.StdEncoding.EncodeToString(pubKey.Bytes()),
+		},
+		"amount":                     selfDelegation.String(),
+		"moniker":                    keyName,
+		"commission-rate":            commissionRate,
+		"commission-max-rate":        "1.0",
+		"commission-max-change-rate": "0.01",
+		"min-self-delegation":        "1",
+	}
+	bz, err := json.Marshal(validator)
+	if err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("create-validator-%s.json", keyName)
+	if err := node.WriteFile(ctx, bz, fileName); err != nil {
+		return "", fmt.Errorf("failed to write validator file: %w", err)
+	}
+
+	return node.ExecTx(ctx, keyName, "staking", "create-validator", filepath.Join(node.HomeDir(), fileName))
+}
+
+// DelegateTokens delegates amount from keyName to the validator with the given valoper address.
+func (node *Node) DelegateTokens(ctx context.Context, keyName, validatorAddr string, amount ibc.WalletAmount) (string, error) {
+	coin := types.NewCoin(amount.Denom, amount.Amount)
+	return node.ExecTx(ctx, keyName, "staking", "delegate", validatorAddr, coin.String())
+}
+
+// QueryDelegation returns the delegation of delegator to validator, including its balance,
+// or nil if there is none, e.g. after the full delegation was unbonded.
+func (node *Node) QueryDelegation(ctx context.Context, delegator, validator string) (*Delegation, error) {
+	stdout, _, err := node.ExecQuery(ctx, "staking", "delegation", delegator, validator)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// SDK v50 wraps the delegation in the query response, older SDKs print it directly.
+	var res struct {
+		DelegationResponse *Delegation `json:"delegation_response"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	if res.DelegationResponse != nil {
+		return res.DelegationResponse, nil
+	}
+
+	var delegation Delegation
+	if err := json.Unmarshal(stdout, &delegation); err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// Unbond undelegates amount delegated by keyName to the validator with the given valoper address.
+func (node *Node) Unbond(ctx context.Context, keyName, validatorAddr string, amount ibc.WalletAmount) (string, error) {
+	coin := types.NewCoin(amount.Denom, amount.Amount)
+	return node.ExecTx(ctx, keyName, "staking", "unbond", validatorAddr, coin.String())
+}
+
+// Redelegate moves amount delegated by keyName from srcVal to dstVal, both valoper addresses.
+func (node *Node) Redelegate(ctx context.Context, keyName, srcVal, dstVal string, amount ibc.WalletAmount) (string, error) {
+	coin := types.NewCoin(amount.Denom, amount.Amount)
+	return node.ExecTx(ctx, keyName, "staking", "redelegate", srcVal, dstVal, coin.String())
+}
+
+// QueryUnbondingDelegations returns the unbonding delegations of delegator from all validators.
+// The result is empty once all unbondings have matured.
+func (node *Node) QueryUnbondingDelegations(ctx context.Context, delegator string) ([]UnbondingDelegation, error) {
+	stdout, _, err := node.ExecQuery(ctx, "staking", "unbonding-delegations", delegator)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var res struct {
+		UnbondingResponses []UnbondingDelegation `json:"unbonding_responses"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return res.UnbondingResponses, nil
+}
@@ -0,0 +1,195 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	"github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+
+	"github.com/decentrio/rollup-e2e-testing/ibc"
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+)
+
+// EscrowAddress returns the bech32 address of the ICS-20 escrow account for the given port and channel.
+func (node *Node) EscrowAddress(portID, channelID string) (string, error) {
+	return types.Bech32ifyAddressBytes(node.Chain.Config().Bech32Prefix, transfertypes.GetEscrowAddress(portID, channelID))
+}
+
+// AssertEscrowConsistency checks that the escrow account of the given port and channel holds exactly
+// expected of denom, e.g. the net amount sent over the channel after refunds and failed acknowledgements.
+func (node *Node) AssertEscrowConsistency(ctx context.Context, portID, channelID, denom string, expected math.Int) error {
+	escrow, err := node.EscrowAddress(portID, channelID)
+	if err != nil {
+		return err
+	}
+
+	balance, err := node.Chain.GetBalance(ctx, escrow, denom)
+	if err != nil {
+		return fmt.Errorf("failed to query escrow balance of %s/%s: %w", portID, channelID, err)
+	}
+	if !balance.Equal(expected) {
+		return fmt.Errorf("escrow %s for %s/%s holds %s%s, expected %s%s", escrow, portID, channelID, balance, denom, expected, denom)
+	}
+	return nil
+}
+
+// AssertNetReceived waits until the receiver's balance of denom is exactly gross minus the fee computed by feeCalc,
+// e.g. the bridging fee deducted from a transfer. A nil feeCalc means no fee.
+// The receiver is expected to hold none of denom before the transfer, as is the case for fresh IBC vouchers.
+func (node *Node) AssertNetReceived(ctx context.Context, receiver, denom string, gross math.Int, feeCalc func(math.Int) math.Int, timeout time.Duration) error {
+	expected := gross
+	if feeCalc != nil {
+		expected = gross.Sub(feeCalc(gross))
+	}
+
+	var balance math.Int
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		var err error
+		balance, err = node.Chain.GetBalance(ctx, receiver, denom)
+		if err != nil {
+			return false, nil
+		}
+		if balance.GT(expected) {
+			return false, fmt.Errorf("balance %s%s exceeds expected net amount %s%s", balance, denom, expected, denom)
+		}
+		return balance.Equal(expected), nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s did not receive net amount %s%s (last balance %s%s): %w", receiver, expected, denom, balance, denom, err)
+	}
+	return nil
+}
+
+// RollappEscrowBalance returns the amount of denom the hub holds in escrow for transfers to the given rollapp,
+// i.e. the balance of the escrow account of the open transfer channel whose light client tracks the rollapp.
+func (node *Node) RollappEscrowBalance(ctx context.Context, rollappChainID, denom string) (math.Int, error) {
+	channelID, err := node.transferChannelTo(ctx, rollappChainID)
+	if err != nil {
+		return math.Int{}, err
+	}
+
+	escrow, err := node.EscrowAddress(transfertypes.PortID, channelID)
+	if err != nil {
+		return math.Int{}, err
+	}
+	return node.Chain.GetBalance(ctx, escrow, denom)
+}
+
+// transferChannelTo returns the id of the open transfer channel to the chain with the given chain id.
+func (node *Node) transferChannelTo(ctx context.Context, counterpartyChainID string) (string, error) {
+	stdout, _, err := node.ExecQuery(ctx, "ibc", "channel", "channels")
+	if err != nil {
+		return "", err
+	}
+
+	var channels struct {
+		Channels []struct {
+			State     string `json:"state"`
+			PortID    string `json:"port_id"`
+			ChannelID string `json:"channel_id"`
+		} `json:"channels"`
+	}
+	if err := json.Unmarshal(stdout, &channels); err != nil {
+		return "", err
+	}
+
+	for _, ch := range channels.Channels {
+		if ch.PortID != transfertypes.PortID || ch.State != "STATE_OPEN" {
+			continue
+		}
+
+		stdout, _, err := node.ExecQuery(ctx, "ibc", "channel", "client-state", ch.PortID, ch.ChannelID)
+		if err != nil {
+			return "", err
+		}
+		var res struct {
+			IdentifiedClientState struct {
+				ClientState struct {
+					ChainID string `json:"chain_id"`
+				} `json:"client_state"`
+			} `json:"identified_client_state"`
+		}
+		if err := json.Unmarshal(stdout, &res); err != nil {
+			return "", err
+		}
+		if res.IdentifiedClientState.ClientState.ChainID == counterpartyChainID {
+			return ch.ChannelID, nil
+		}
+	}
+	return "", fmt.Errorf("no open transfer channel to %s", counterpartyChainID)
+}
+
+// transferLegTimeout bounds the wait for each leg of RoundTripTransfer to be relayed.
+const transferLegTimeout = 2 * time.Minute
+
+// RoundTripTransfer sends amount of a denom native to chainA from keyA to chainB over channelAtoB, then sends the
+// received vouchers back over channelBtoA, which must be the counterparty of channelAtoB, and returns an error
+// unless keyA's balance on chainA is back to its start minus the fees paid on chainA.
+// keyA must exist on both chains; amount.Address, if set, must be keyA's address on chainB.
+// A relayer must be relaying the channel.
+func RoundTripTransfer(ctx context.Context, chainA, chainB *Node, channelAtoB, channelBtoA, keyA string, amount ibc.WalletAmount) error {
+	senderA, err := chainA.AccountKeyBech32(ctx, keyA)
+	if err != nil {
+		return err
+	}
+	receiverB, err := chainB.AccountKeyBech32(ctx, keyA)
+	if err != nil {
+		return err
+	}
+	if amount.Address != "" && amount.Address != receiverB {
+		return fmt.Errorf("receiver %s is not the address %s of %s on %s", amount.Address, receiverB, keyA, chainB.Chain.Config().ChainID)
+	}
+	voucher := transfertypes.ParseDenomTrace(transfertypes.GetPrefixedDenom(transfertypes.PortID, channelBtoA, amount.Denom)).IBCDenom()
+
+	startA, err := chainA.Chain.GetBalance(ctx, senderA, amount.Denom)
+	if err != nil {
+		return err
+	}
+	startB, err := chainB.Chain.GetBalance(ctx, receiverB, voucher)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := chainA.SendIBCTransfer(ctx, channelAtoB, keyA, ibc.WalletAmount{Address: receiverB, Denom: amount.Denom, Amount: amount.Amount}, ibc.TransferOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to send %s%s to %s: %w", amount.Amount, amount.Denom, chainB.Chain.Config().ChainID, err)
+	}
+	fees, err := chainA.TxPaidFee(ctx, txHash)
+	if err != nil {
+		return err
+	}
+	if err := chainB.waitForBalance(ctx, receiverB, voucher, startB.Add(amount.Amount), transferLegTimeout); err != nil {
+		return fmt.Errorf("transfer to %s was not received: %w", chainB.Chain.Config().ChainID, err)
+	}
+
+	_, err = chainB.SendIBCTransfer(ctx, channelBtoA, keyA, ibc.WalletAmount{Address: senderA, Denom: voucher, Amount: amount.Amount}, ibc.TransferOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to send %s%s back to %s: %w", amount.Amount, voucher, chainA.Chain.Config().ChainID, err)
+	}
+	expected := startA.Sub(fees.AmountOf(amount.Denom))
+	if err := chainA.waitForBalance(ctx, senderA, amount.Denom, expected, transferLegTimeout); err != nil {
+		return fmt.Errorf("balance of %s on %s was not restored: %w", senderA, chainA.Chain.Config().ChainID, err)
+	}
+	return nil
+}
+
+// waitForBalance polls the balance of address in denom until it equals expected.
+func (node *Node) waitForBalance(ctx context.Context, address, denom string, expected math.Int, timeout time.Duration) error {
+	var balance math.Int
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		var err error
+		balance, err = node.Chain.GetBalance(ctx, address, denom)
+		if err != nil {
+			return false, nil
+		}
+		return balance.Equal(expected), nil
+	})
+	if err != nil {
+		return fmt.Errorf("balance of %s is %s%s, expected %s%s: %w", address, balance, denom, expected, denom, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// QueryModuleVersions returns the consensus version of every module of the chain, keyed by module name.
+func (node *Node) QueryModuleVersions(ctx context.Context) (map[string]uint64, error) {
+	// SDK v50 renamed the query command, older SDKs only know the snake case one.
+	stdout, _, err := node.ExecQuery(ctx, "upgrade", "module-versions")
+	if err != nil && strings.Contains(err.Error(), "unknown command") {
+		stdout, _, err = node.ExecQuery(ctx, "upgrade", "module_versions")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		ModuleVersions []struct {
+			Name string `json:"name"`
+			// Version is a string in proto json and a number in amino json.
+			Version json.RawMessage `json:"version"`
+		} `json:"module_versions"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]uint64, len(res.ModuleVersions))
+	for _, mv := range res.ModuleVersions {
+		version, err := strconv.ParseUint(strings.Trim(string(mv.Version), `"`), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version of module %s: %w", mv.Name, err)
+		}
+		versions[mv.Name] = version
+	}
+	return versions, nil
+}
+
+// AssertModuleMigrated returns an error unless module is now at consensus version toVersion,
+// proving an upgrade ran its store migrations from fromVersion, which the caller should have
+// read with QueryModuleVersions before the upgrade.
+func (node *Node) AssertModuleMigrated(ctx context.Context, module string, fromVersion, toVersion uint64) error {
+	if toVersion <= fromVersion {
+		return fmt.Errorf("module %s cannot migrate from version %d to version %d", module, fromVersion, toVersion)
+	}
+
+	versions, err := node.QueryModuleVersions(ctx)
+	if err != nil {
+		return err
+	}
+	version, ok := versions[module]
+	if !ok {
+		return fmt.Errorf("module %s not found in module versions", module)
+	}
+	if version != toVersion {
+		return fmt.Errorf("expected module %s to be migrated from version %d to version %d, it is at version %d",
+			module, fromVersion, toVersion, version)
+	}
+	return nil
+}
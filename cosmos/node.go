@@ -4,19 +4,28 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
+	"net"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"cosmossdk.io/math"
+	"github.com/BurntSushi/toml"
 	"github.com/avast/retry-go/v4"
+	abcitypes "github.com/cometbft/cometbft/abci/types"
 	tmjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/p2p"
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
@@ -26,11 +35,15 @@ import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	paramsutils "github.com/cosmos/cosmos-sdk/x/params/client/utils"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/cosmos/go-bip39"
+	"github.com/docker/docker/api/types/container"
 	dockerclient "github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
@@ -51,12 +64,29 @@ type Node struct {
 	Client       rpcclient.Client
 	TestName     string
 	Image        ibc.DockerImage
+	// KeyringDir is the keyring directory used by the hub key helpers, e.g. CreateHubKey. Defaults to the home dir.
+	KeyringDir string
+	// GOGC and GOMEMLIMIT tune the Go runtime of the node process when set before CreateNodeContainer.
+	GOGC       string
+	GOMEMLIMIT string
+	// CPUShares and MemoryBytes constrain the node container when set before CreateNodeContainer. Zero means no limit.
+	CPUShares   int64
+	MemoryBytes int64
+
+	// recorder records every tx broadcast by the node while attached, see NewTxRecorder.
+	recorder atomic.Pointer[TxRecorder]
+
+	// homeSubdir overrides the name of the home dir under /var/cosmos-chain, see SetHomeSubdir.
+	homeSubdir string
 
 	lock sync.Mutex
 	log  *zap.Logger
 
 	containerLifecycle *dockerutil.ContainerLifecycle
 
+	timingsMu      sync.Mutex
+	startupTimings StartupTimings
+
 	// Ports set during StartContainer.
 	hostRPCPort  string
 	hostAPIPort  string
@@ -137,6 +167,15 @@ func (node *Node) CliContext() client.Context {
 	}
 }
 
+// CliContextWith returns CliContext with opts applied in order, e.g. to set the broadcast mode or fee granter.
+func (node *Node) CliContextWith(opts ...func(client.Context) client.Context) client.Context {
+	clientCtx := node.CliContext()
+	for _, opt := range opts {
+		clientCtx = opt(clientCtx)
+	}
+	return clientCtx
+}
+
 // Name of the test node container
 func (node *Node) Name() string {
 	var nodeType string
@@ -213,9 +252,91 @@ func (node *Node) Bind() []string {
 }
 
 func (node *Node) HomeDir() string {
+	if node.homeSubdir != "" {
+		return path.Join("/var/cosmos-chain", node.homeSubdir)
+	}
 	return path.Join("/var/cosmos-chain", node.Chain.Config().Name+node.VolumeName)
 }
 
+// homeDirs maps the home dirs claimed by the nodes of this process to their node, see AssertUniqueHomeDirs.
+// Claims are scoped to the test setting up the node, so claims a test does not release never affect other tests.
+var (
+	homeDirsMu sync.Mutex
+	homeDirs   = make(map[homeDirKey]*Node)
+)
+
+// homeDirKey identifies a home dir claim of a test.
+type homeDirKey struct {
+	testName string
+	home     string
+}
+
+// claimHomeDir claims the home dir of the node, returning an error if another node claimed it already.
+func (node *Node) claimHomeDir() error {
+	homeDirsMu.Lock()
+	defer homeDirsMu.Unlock()
+
+	home := node.HomeDir()
+	key := homeDirKey{testName: node.TestName, home: home}
+	if owner, ok := homeDirs[key]; ok && owner != node {
+		return fmt.Errorf("nodes %s and %s share home dir %s", owner.Name(), node.Name(), home)
+	}
+	homeDirs[key] = node
+	return nil
+}
+
+// releaseHomeDir releases the claim of the node on its home dir, if it holds it.
+func (node *Node) releaseHomeDir() {
+	homeDirsMu.Lock()
+	defer homeDirsMu.Unlock()
+
+	key := homeDirKey{testName: node.TestName, home: node.HomeDir()}
+	if homeDirs[key] == node {
+		delete(homeDirs, key)
+	}
+}
+
+// homeVolume returns the volume and chain name arguments for which the dockerutil file helpers,
+// which mount /tmp/<chainName><volumeName>, access the home dir of the node.
+func (node *Node) homeVolume() (volumeName, chainName string) {
+	if node.homeSubdir != "" {
+		return "", node.homeSubdir
+	}
+	return node.VolumeName, node.Chain.Config().Name
+}
+
+// SetHomeSubdir overrides the name of the node home dir under /var/cosmos-chain, which is the /tmp dir of the
+// docker host and so is shared by all tests running on it, e.g. to isolate parallel tests.
+// It must be called before the node files are initialized. The new home dir is claimed, see AssertUniqueHomeDirs.
+func (node *Node) SetHomeSubdir(ctx context.Context, subdir string) error {
+	if subdir == "" || path.Base(subdir) != subdir {
+		return fmt.Errorf("invalid home subdir %q", subdir)
+	}
+	node.releaseHomeDir()
+	node.homeSubdir = subdir
+	if err := node.claimHomeDir(); err != nil {
+		return err
+	}
+
+	return dockerutil.SetVolumeOwner(ctx, dockerutil.VolumeOwnerOptions{
+		Log: node.logger(),
+
+		Client: node.DockerClient,
+
+		ChainName: subdir,
+		ImageRef:  node.Image.Ref(),
+		TestName:  node.TestName,
+		UidGid:    node.Image.UidGid,
+	})
+}
+
+// ModifyTomlConfigFile reads, modifies, then overwrites a toml config file in the node home dir,
+// e.g. "config/config.toml".
+func (node *Node) ModifyTomlConfigFile(ctx context.Context, relPath string, modification testutil.Toml) error {
+	volumeName, chainName := node.homeVolume()
+	return testutil.ModifyTomlConfigFile(ctx, node.logger(), node.DockerClient, node.TestName, volumeName, chainName, relPath, modification)
+}
+
 // SetTestConfig modifies the config to reasonable values for use within e2e-test.
 func (node *Node) SetTestConfig(ctx context.Context) error {
 	c := make(testutil.Toml)
@@ -247,16 +368,7 @@ func (node *Node) SetTestConfig(ctx context.Context) error {
 
 	c["rpc"] = rpc
 
-	if err := testutil.ModifyTomlConfigFile(
-		ctx,
-		node.logger(),
-		node.DockerClient,
-		node.TestName,
-		node.VolumeName,
-		node.Chain.Config().Name,
-		"config/config.toml",
-		c,
-	); err != nil {
+	if err := node.ModifyTomlConfigFile(ctx, "config/config.toml", c); err != nil {
 		return err
 	}
 
@@ -279,16 +391,52 @@ func (node *Node) SetTestConfig(ctx context.Context) error {
 
 	a["api"] = api
 
-	return testutil.ModifyTomlConfigFile(
-		ctx,
-		node.logger(),
-		node.DockerClient,
-		node.TestName,
-		node.VolumeName,
-		node.Chain.Config().Name,
-		"config/app.toml",
-		a,
-	)
+	return node.ModifyTomlConfigFile(ctx, "config/app.toml", a)
+}
+
+// ValidateConfig reads back config.toml and app.toml and checks that the settings applied by SetTestConfig
+// are present and well-formed, catching broken config overrides before the node is started.
+func (node *Node) ValidateConfig(ctx context.Context) error {
+	var config struct {
+		RPC struct {
+			Laddr string `toml:"laddr"`
+		} `toml:"rpc"`
+	}
+	if err := node.readTomlConfig(ctx, "config/config.toml", &config); err != nil {
+		return err
+	}
+	u, err := url.Parse(config.RPC.Laddr)
+	if err != nil || u.Scheme == "" || u.Port() == "" {
+		return fmt.Errorf("config.toml: invalid rpc.laddr %q", config.RPC.Laddr)
+	}
+
+	var app struct {
+		MinimumGasPrices string `toml:"minimum-gas-prices"`
+		GRPC             struct {
+			Address string `toml:"address"`
+		} `toml:"grpc"`
+	}
+	if err := node.readTomlConfig(ctx, "config/app.toml", &app); err != nil {
+		return err
+	}
+	if _, _, err := net.SplitHostPort(app.GRPC.Address); err != nil {
+		return fmt.Errorf("app.toml: invalid grpc.address %q: %w", app.GRPC.Address, err)
+	}
+	if _, err := types.ParseDecCoins(app.MinimumGasPrices); err != nil || app.MinimumGasPrices == "" {
+		return fmt.Errorf("app.toml: invalid minimum-gas-prices %q", app.MinimumGasPrices)
+	}
+	return nil
+}
+
+func (node *Node) readTomlConfig(ctx context.Context, relPath string, v any) error {
+	bz, err := node.ReadFile(ctx, relPath)
+	if err != nil {
+		return err
+	}
+	if err := toml.Unmarshal(bz, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", relPath, err)
+	}
+	return nil
 }
 
 // SetPeers modifies the config persistent_peers for a node
@@ -300,16 +448,24 @@ func (node *Node) SetPeers(ctx context.Context, peers string) error {
 	p2p["persistent_peers"] = peers
 	c["p2p"] = p2p
 
-	return testutil.ModifyTomlConfigFile(
-		ctx,
-		node.logger(),
-		node.DockerClient,
-		node.TestName,
-		node.VolumeName,
-		node.Chain.Config().Name,
-		"config/config.toml",
-		c,
-	)
+	return node.ModifyTomlConfigFile(ctx, "config/config.toml", c)
+}
+
+// SetConsensusTimeouts modifies the consensus timeouts in config.toml for a node,
+// e.g. "timeout_prevote" or "timeout_precommit_delta". Keys not given are left unchanged.
+func (node *Node) SetConsensusTimeouts(ctx context.Context, timeouts map[string]time.Duration) error {
+	c := make(testutil.Toml)
+	consensus := make(testutil.Toml)
+
+	for key, timeout := range timeouts {
+		if !strings.HasPrefix(key, "timeout_") {
+			return fmt.Errorf("%q is not a consensus timeout key", key)
+		}
+		consensus[key] = timeout.String()
+	}
+	c["consensus"] = consensus
+
+	return node.ModifyTomlConfigFile(ctx, "config/config.toml", c)
 }
 
 func (node *Node) Height(ctx context.Context) (uint64, error) {
@@ -321,6 +477,94 @@ func (node *Node) Height(ctx context.Context) (uint64, error) {
 	return uint64(height), nil
 }
 
+// EarliestHeight returns the height of the earliest block the node has, e.g. the genesis initial height,
+// or 0 if it has no block yet.
+func (node *Node) EarliestHeight(ctx context.Context) (uint64, error) {
+	res, err := node.Client.Status(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("tendermint rpc client status: %w", err)
+	}
+	return uint64(res.SyncInfo.EarliestBlockHeight), nil
+}
+
+// WaitForHeight polls the node's latest height about once per block until it reaches target,
+// or returns an error once ctx expires. Failing status queries, e.g. while the node restarts, are retried.
+func (node *Node) WaitForHeight(ctx context.Context, target uint64) error {
+	ticker := time.NewTicker(blockTime * time.Second)
+	defer ticker.Stop()
+
+	var (
+		height  uint64
+		lastErr error
+	)
+	for {
+		height, lastErr = node.Height(ctx)
+		if lastErr == nil && height >= target {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("node %s did not reach height %d: %w", node.Name(), target, lastErr)
+			}
+			return fmt.Errorf("node %s did not reach height %d, at height %d: %w", node.Name(), target, height, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForHeightWithTimeout is like WaitForHeight, giving up after timeout.
+func (node *Node) WaitForHeightWithTimeout(ctx context.Context, target uint64, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return node.WaitForHeight(ctx, target)
+}
+
+// NextValidatorsHash returns the hash of the validator set of the block after height, as committed in the header at height.
+func (node *Node) NextValidatorsHash(ctx context.Context, height int64) ([]byte, error) {
+	block, err := node.Client.Block(ctx, &height)
+	if err != nil {
+		return nil, fmt.Errorf("tendermint rpc get block: %w", err)
+	}
+	return block.Block.NextValidatorsHash, nil
+}
+
+// MeasureCatchupRate watches the node's height while it syncs up to target and returns the average
+// number of blocks it synced per second.
+func (node *Node) MeasureCatchupRate(ctx context.Context, target uint64, timeout time.Duration) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status, err := node.Client.Status(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("tendermint rpc client status: %w", err)
+	}
+	startHeight, startTime := status.SyncInfo.LatestBlockHeight, time.Now()
+	if uint64(startHeight) >= target {
+		return 0, fmt.Errorf("node is already at height %d, past target %d", startHeight, target)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("node did not reach height %d within %s: %w", target, timeout, ctx.Err())
+		case <-ticker.C:
+		}
+
+		status, err := node.Client.Status(ctx)
+		if err != nil {
+			continue
+		}
+		height := status.SyncInfo.LatestBlockHeight
+		if uint64(height) >= target {
+			return float64(height-startHeight) / time.Since(startTime).Seconds(), nil
+		}
+	}
+}
+
 // FindTxs implements blockdb.BlockSaver.
 func (node *Node) FindTxs(ctx context.Context, height uint64) ([]blockdb.Tx, error) {
 	h := int64(height)
@@ -358,43 +602,155 @@ func (node *Node) FindTxs(ctx context.Context, height uint64) ([]blockdb.Tx, err
 
 		rTx := blockRes.TxsResults[i]
 
-		newTx.Events = make([]blockdb.Event, len(rTx.Events))
-		for j, e := range rTx.Events {
-			attrs := make([]blockdb.EventAttribute, len(e.Attributes))
-			for k, attr := range e.Attributes {
-				attrs[k] = blockdb.EventAttribute{
-					Key:   string(attr.Key),
-					Value: string(attr.Value),
-				}
-			}
-			newTx.Events[j] = blockdb.Event{
-				Type:       e.Type,
-				Attributes: attrs,
-			}
-		}
+		newTx.Events = toBlockdbEvents(rTx.Events)
 		txs = append(txs, newTx)
 	}
 	if len(blockRes.FinalizeBlockEvents) > 0 {
 		finalizeBlockTx := blockdb.Tx{
 			Data: []byte(`{"data":"finalize_block","note":"this is a transaction artificially created for debugging purposes"}`),
 		}
-		finalizeBlockTx.Events = make([]blockdb.Event, len(blockRes.FinalizeBlockEvents))
-		for i, e := range blockRes.FinalizeBlockEvents {
-			attrs := make([]blockdb.EventAttribute, len(e.Attributes))
-			for j, attr := range e.Attributes {
-				attrs[j] = blockdb.EventAttribute{
-					Key:   string(attr.Key),
-					Value: string(attr.Value),
-				}
+		finalizeBlockTx.Events = toBlockdbEvents(blockRes.FinalizeBlockEvents)
+		txs = append(txs, finalizeBlockTx)
+	}
+	return txs, nil
+}
+
+// BlockEvents returns the events emitted at height, split into begin block, transaction and end block events.
+// Finalize block events are attributed to begin or end block by the "mode" attribute set by the SDK.
+func (node *Node) BlockEvents(ctx context.Context, height uint64) (begin, tx, end []blockdb.Event, err error) {
+	h := int64(height)
+	blockRes, err := node.Client.BlockResults(ctx, &h)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get block results at height %d: %w", height, err)
+	}
+
+	for _, rTx := range blockRes.TxsResults {
+		tx = append(tx, toBlockdbEvents(rTx.Events)...)
+	}
+	for _, e := range toBlockdbEvents(blockRes.FinalizeBlockEvents) {
+		isBegin := false
+		for _, attr := range e.Attributes {
+			if attr.Key == "mode" && attr.Value == "BeginBlock" {
+				isBegin = true
+				break
 			}
-			finalizeBlockTx.Events[i] = blockdb.Event{
-				Type:       e.Type,
-				Attributes: attrs,
+		}
+		if isBegin {
+			begin = append(begin, e)
+		} else {
+			end = append(end, e)
+		}
+	}
+	return begin, tx, end, nil
+}
+
+func toBlockdbEvents(events []abcitypes.Event) []blockdb.Event {
+	res := make([]blockdb.Event, len(events))
+	for i, e := range events {
+		attrs := make([]blockdb.EventAttribute, len(e.Attributes))
+		for j, attr := range e.Attributes {
+			attrs[j] = blockdb.EventAttribute{
+				Key:   string(attr.Key),
+				Value: string(attr.Value),
+			}
+		}
+		res[i] = blockdb.Event{
+			Type:       e.Type,
+			Attributes: attrs,
+		}
+	}
+	return res
+}
+
+// GetTxEvents returns the events of the tx with the given hash in the shape FindTxs uses.
+// Attributes which tendermint < v0.37 emitted base64 encoded are decoded.
+func (node *Node) GetTxEvents(ctx context.Context, txHash string) ([]blockdb.Event, error) {
+	txResp, err := node.GetTransaction(node.CliContext(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+
+	events := toBlockdbEvents(txResp.Events)
+	for _, event := range events {
+		decodeEventAttributes(event.Attributes)
+	}
+	return events, nil
+}
+
+// decodeEventAttributes decodes the keys and values of attrs in place if all of them are base64 encoded.
+// Plain attribute keys are identifiers, which are rarely valid base64 and never decode to printable text.
+func decodeEventAttributes(attrs []blockdb.EventAttribute) {
+	decoded := make([]blockdb.EventAttribute, len(attrs))
+	for i, attr := range attrs {
+		key, err := base64.StdEncoding.DecodeString(attr.Key)
+		if err != nil || len(key) == 0 || !isPrintable(key) {
+			return
+		}
+		value, err := base64.StdEncoding.DecodeString(attr.Value)
+		if err != nil {
+			return
+		}
+		decoded[i] = blockdb.EventAttribute{Key: string(key), Value: string(value)}
+	}
+	copy(attrs, decoded)
+}
+
+// isPrintable reports whether b only holds printable ASCII characters.
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForMessageType scans blocks produced from now on and returns the height of the first block
+// containing a message with the given type url, e.g. "/ibc.core.channel.v1.MsgRecvPacket".
+func (node *Node) WaitForMessageType(ctx context.Context, msgTypeURL string, timeout time.Duration) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	next, err := node.Height(ctx)
+	if err != nil {
+		return 0, err
+	}
+	next++
+
+	interfaceRegistry := node.Chain.Config().EncodingConfig.InterfaceRegistry
+	ticker := time.NewTicker(blockTime * time.Second / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("no message of type %s found within %s: %w", msgTypeURL, timeout, ctx.Err())
+		case <-ticker.C:
+		}
+
+		current, err := node.Height(ctx)
+		if err != nil {
+			continue
+		}
+		for ; next <= current; next++ {
+			h := int64(next)
+			block, err := node.Client.Block(ctx, &h)
+			if err != nil {
+				return 0, fmt.Errorf("failed to get block %d: %w", next, err)
+			}
+			for _, tx := range block.Block.Txs {
+				sdkTx, err := decodeTX(interfaceRegistry, tx)
+				if err != nil {
+					node.logger().Info("Failed to decode tx", zap.Uint64("height", next), zap.Error(err))
+					continue
+				}
+				for _, msg := range sdkTx.GetMsgs() {
+					if types.MsgTypeURL(msg) == msgTypeURL {
+						return next, nil
+					}
+				}
 			}
 		}
-		txs = append(txs, finalizeBlockTx)
 	}
-	return txs, nil
 }
 
 // TxCommand is a helper to retrieve a full command for broadcasting a tx
@@ -430,10 +786,40 @@ func (node *Node) TxCommand(keyName string, command ...string) []string {
 
 // ExecTx executes a transaction, waits for 2 blocks if successful, then returns the tx hash.
 func (node *Node) ExecTx(ctx context.Context, keyName string, command ...string) (string, error) {
+	return node.ExecTxWithOptions(ctx, keyName, DefaultTxOptions(), command...)
+}
+
+// ExecTxWithResponse executes a transaction, waits for 2 blocks if successful, then returns the committed tx response.
+// If the tx is rejected before inclusion, the returned response only holds the hash, code and raw log of the rejection.
+// Unlike ExecTx, it queries the tx after waiting, so the node must index txs.
+func (node *Node) ExecTxWithResponse(ctx context.Context, keyName string, command ...string) (*types.TxResponse, error) {
+	txHash, err := node.ExecTx(ctx, keyName, command...)
+	if err != nil {
+		var rejected *txRejectedError
+		if errors.As(err, &rejected) {
+			return &types.TxResponse{TxHash: txHash, Code: rejected.code, RawLog: rejected.rawLog}, err
+		}
+		return nil, err
+	}
+
+	txResp, err := node.GetTransaction(node.CliContext(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+	return txResp, nil
+}
+
+// ExecTxWithOptions executes a transaction like ExecTx, waiting for opts.WaitForBlocks blocks if successful.
+// With WaitForBlocks 0 it returns as soon as the tx is accepted into the mempool, so callers sending many txs
+// can wait once at the end.
+func (node *Node) ExecTxWithOptions(ctx context.Context, keyName string, opts TxOptions, command ...string) (string, error) {
 	node.lock.Lock()
 	defer node.lock.Unlock()
 
-	stdout, _, err := node.Exec(ctx, node.TxCommand(keyName, command...), nil)
+	if opts.BroadcastMode != "" {
+		command = append(append([]string{}, command...), "--broadcast-mode", opts.BroadcastMode)
+	}
+	stdout, _, err := node.execTxCommand(ctx, keyName, command...)
 	if err != nil {
 		return "", err
 	}
@@ -443,14 +829,178 @@ func (node *Node) ExecTx(ctx context.Context, keyName string, command ...string)
 		return "", err
 	}
 	if output.Code != 0 {
-		return output.TxHash, fmt.Errorf("transaction failed with code %d: %s", output.Code, output.RawLog)
+		return output.TxHash, &txRejectedError{code: uint32(output.Code), rawLog: output.RawLog}
 	}
-	if err := testutil.WaitForBlocks(ctx, 2, node); err != nil {
-		return "", err
+	if opts.WaitForBlocks > 0 {
+		if err := testutil.WaitForBlocks(ctx, opts.WaitForBlocks, node); err != nil {
+			return "", err
+		}
 	}
 	return output.TxHash, nil
 }
 
+// execTxCommand runs the tx command with the tx flags of keyName, recording it first if a TxRecorder is attached.
+// Every tx broadcast by the node goes through it.
+func (node *Node) execTxCommand(ctx context.Context, keyName string, command ...string) ([]byte, []byte, error) {
+	if r := node.recorder.Load(); r != nil {
+		if err := r.record(keyName, command); err != nil {
+			return nil, nil, err
+		}
+	}
+	return node.Exec(ctx, node.TxCommand(keyName, command...), nil)
+}
+
+// txRejectedError is returned by ExecTxWithOptions for a tx rejected before inclusion in a block.
+type txRejectedError struct {
+	code   uint32
+	rawLog string
+}
+
+func (e *txRejectedError) Error() string {
+	return fmt.Sprintf("transaction failed with code %d: %s", e.code, e.rawLog)
+}
+
+// AssertTxRejectedBelowMinGas submits the tx with gas prices 10% below the chain's configured minimum gas prices
+// and returns an error unless the tx is rejected for insufficient fees. The command must not set --fees or
+// --gas-prices itself, as those would override the gas prices below the minimum.
+func (node *Node) AssertTxRejectedBelowMinGas(ctx context.Context, keyName string, command []string) error {
+	for _, arg := range command {
+		for _, flag := range []string{"--fees", "--gas-prices"} {
+			if arg == flag || strings.HasPrefix(arg, flag+"=") {
+				return fmt.Errorf("command must not set %s, the gas prices below the minimum are set instead", flag)
+			}
+		}
+	}
+
+	minGasPrices, err := types.ParseDecCoins(node.Chain.Config().GasPrices)
+	if err != nil {
+		return fmt.Errorf("failed to parse gas prices: %w", err)
+	}
+	if !minGasPrices.IsAllPositive() {
+		return fmt.Errorf("chain has no minimum gas price to go below: %q", node.Chain.Config().GasPrices)
+	}
+	belowMin := minGasPrices.MulDec(math.LegacyNewDecWithPrec(9, 1))
+
+	cmd := append(append([]string{}, command...), "--gas-prices", belowMin.String())
+	stdout, stderr, err := node.execTxCommand(ctx, keyName, cmd...)
+	if err != nil {
+		// Some versions reject the tx client side and exit with an error instead of printing the response.
+		if strings.Contains(string(stderr)+err.Error(), "insufficient fee") {
+			return nil
+		}
+		return err
+	}
+
+	output := CosmosTx{}
+	if err := json.Unmarshal(stdout, &output); err != nil {
+		return err
+	}
+	if uint32(output.Code) != sdkerrors.ErrInsufficientFee.ABCICode() {
+		return fmt.Errorf("expected tx with gas prices %s to fail with insufficient fee (code %d), got code %d: %s",
+			belowMin, sdkerrors.ErrInsufficientFee.ABCICode(), output.Code, output.RawLog)
+	}
+	return nil
+}
+
+// AssertReplayRejected broadcasts the signed tx, waits for it to be committed, broadcasts it again and returns
+// an error unless the replay is rejected with an account sequence mismatch. If the node's mempool cache turns the
+// replay away as a known tx before it reaches the application, the replay is simulated against the application
+// instead, which must reject it with the same account sequence mismatch.
+func (node *Node) AssertReplayRejected(ctx context.Context, signedTxBytes []byte) error {
+	res, err := node.Client.BroadcastTxSync(ctx, signedTxBytes)
+	if err != nil {
+		return fmt.Errorf("failed to broadcast tx: %w", err)
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("transaction failed with code %d: %s", res.Code, res.Log)
+	}
+	if err := testutil.WaitForBlocks(ctx, 2, node); err != nil {
+		return err
+	}
+
+	replay, err := node.Client.BroadcastTxSync(ctx, signedTxBytes)
+	if err != nil {
+		if !strings.Contains(err.Error(), "tx already exists in cache") {
+			return fmt.Errorf("failed to broadcast replayed tx: %w", err)
+		}
+		sim, err := node.Client.ABCIQuery(ctx, "/app/simulate", signedTxBytes)
+		if err != nil {
+			return fmt.Errorf("failed to simulate replayed tx: %w", err)
+		}
+		if sim.Response.Codespace != sdkerrors.ErrWrongSequence.Codespace() ||
+			sim.Response.Code != sdkerrors.ErrWrongSequence.ABCICode() {
+			return fmt.Errorf("replayed tx was rejected by the mempool cache, expected simulating it to fail with account sequence mismatch (code %d), got code %d: %s",
+				sdkerrors.ErrWrongSequence.ABCICode(), sim.Response.Code, sim.Response.Log)
+		}
+		return nil
+	}
+	if replay.Code != sdkerrors.ErrWrongSequence.ABCICode() {
+		return fmt.Errorf("expected replayed tx %s to fail with account sequence mismatch (code %d), got code %d: %s",
+			replay.Hash, sdkerrors.ErrWrongSequence.ABCICode(), replay.Code, replay.Log)
+	}
+	return nil
+}
+
+// AssertBlockGasLimitEnforced broadcasts three self transfers from keyName, each wanting just over half of the
+// block max gas, without waiting in between, and returns an error if any two of them land in the same block.
+// The chain must have a block max gas limit set, see SetBlockMaxGas.
+func (node *Node) AssertBlockGasLimitEnforced(ctx context.Context, keyName string) error {
+	params, err := node.Client.ConsensusParams(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get consensus params: %w", err)
+	}
+	maxGas := params.ConsensusParams.Block.MaxGas
+	if maxGas <= 0 {
+		return fmt.Errorf("block max gas is not limited (%d)", maxGas)
+	}
+
+	addr, err := node.AccountKeyBech32(ctx, keyName)
+	if err != nil {
+		return err
+	}
+	sequence, err := node.accountSequence(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	const numTxs = 3
+	gas := strconv.FormatInt(maxGas/2+1, 10)
+	amount := "1" + node.Chain.Config().Denom
+	hashes := make([]string, 0, numTxs)
+	for i := uint64(0); i < numTxs; i++ {
+		stdout, _, err := node.execTxCommand(ctx, keyName, "bank", "send", addr, addr, amount,
+			"--gas", gas, "--sequence", strconv.FormatUint(sequence+i, 10))
+		if err != nil {
+			return err
+		}
+		output := CosmosTx{}
+		if err := json.Unmarshal(stdout, &output); err != nil {
+			return err
+		}
+		if output.Code != 0 {
+			return fmt.Errorf("transaction failed with code %d: %s", output.Code, output.RawLog)
+		}
+		hashes = append(hashes, output.TxHash)
+	}
+
+	if err := testutil.WaitForBlocks(ctx, numTxs+1, node); err != nil {
+		return err
+	}
+
+	heights := make(map[int64]string, numTxs)
+	for _, hash := range hashes {
+		txResp, err := node.GetTransaction(node.CliContext(), hash)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction %s: %w", hash, err)
+		}
+		if other, ok := heights[txResp.Height]; ok {
+			return fmt.Errorf("txs %s and %s wanting %s gas each landed in block %d with max gas %d", other, hash, gas, txResp.Height, maxGas)
+		}
+		heights[txResp.Height] = hash
+	}
+	return nil
+}
+
 // NodeCommand is a helper to retrieve a full command for a chain node binary.
 // when interactions with the RPC endpoint are necessary.
 // For example, if chain node binary is `gaiad`, and desired command is `gaiad keys show key1`,
@@ -501,6 +1051,70 @@ func (node *Node) ExecQuery(ctx context.Context, command ...string) ([]byte, []b
 	return node.Exec(ctx, node.QueryCommand(command...), nil)
 }
 
+// QueryJSON runs the query command and unmarshals its json output into out.
+// Some malformed queries print an error to stderr but exit 0; the stderr content is returned as error in that case.
+func (node *Node) QueryJSON(ctx context.Context, out any, command ...string) error {
+	stdout, stderr, err := node.ExecQuery(ctx, command...)
+	if err != nil {
+		return err
+	}
+
+	errOutput := strings.TrimSpace(string(stderr))
+	if len(bytes.TrimSpace(stdout)) == 0 {
+		if errOutput != "" {
+			return fmt.Errorf("query %v printed no output: %s", command, errOutput)
+		}
+		return fmt.Errorf("query %v printed no output", command)
+	}
+	if strings.Contains(errOutput, "Error:") {
+		return fmt.Errorf("query %v failed: %s", command, errOutput)
+	}
+	if err := json.Unmarshal(stdout, out); err != nil {
+		if errOutput != "" {
+			return fmt.Errorf("failed to unmarshal output of query %v: %w (stderr: %s)", command, err, errOutput)
+		}
+		return fmt.Errorf("failed to unmarshal output of query %v: %w", command, err)
+	}
+	return nil
+}
+
+// BenchmarkQuery runs the cli and grpc query functions iterations times each and returns their average latencies.
+// It is intended to compare the cost of querying through the chain binary against querying the gRPC endpoint directly.
+func (node *Node) BenchmarkQuery(ctx context.Context, iterations int, cli func() error, grpc func() error) (cliAvg, grpcAvg time.Duration, err error) {
+	if iterations <= 0 {
+		return 0, 0, fmt.Errorf("iterations must be positive, got %d", iterations)
+	}
+
+	measure := func(name string, fn func() error) (time.Duration, error) {
+		var total time.Duration
+		for i := 0; i < iterations; i++ {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			start := time.Now()
+			if err := fn(); err != nil {
+				return 0, fmt.Errorf("%s query (iteration %d): %w", name, i, err)
+			}
+			total += time.Since(start)
+		}
+		return total / time.Duration(iterations), nil
+	}
+
+	if cliAvg, err = measure("cli", cli); err != nil {
+		return 0, 0, err
+	}
+	if grpcAvg, err = measure("grpc", grpc); err != nil {
+		return 0, 0, err
+	}
+
+	node.logger().Info("Benchmarked query latency",
+		zap.Int("iterations", iterations),
+		zap.Duration("cli_avg", cliAvg),
+		zap.Duration("grpc_avg", grpcAvg),
+	)
+	return cliAvg, grpcAvg, nil
+}
+
 // CondenseMoniker fits a moniker into the cosmos character limit for monikers.
 // If the moniker already fits, it is returned unmodified.
 // Otherwise, the middle is truncated, and a hash is appended to the end
@@ -544,7 +1158,26 @@ func (node *Node) InitHomeFolder(ctx context.Context) error {
 // docker volume relative to the home directory
 func (node *Node) WriteFile(ctx context.Context, content []byte, relPath string) error {
 	fw := dockerutil.NewFileWriter(node.logger(), node.DockerClient, node.TestName)
-	return fw.WriteFile(ctx, node.VolumeName, node.Chain.Config().Name, relPath, content)
+	volumeName, chainName := node.homeVolume()
+	return fw.WriteFile(ctx, volumeName, chainName, relPath, content)
+}
+
+// tempJSONFileName returns a json file name starting with prefix, unique to the call so that concurrent helpers
+// writing to the home directory do not overwrite each other's files.
+func tempJSONFileName(prefix string) string {
+	return prefix + "-" + dockerutil.RandLowerCaseLetterString(8) + ".json"
+}
+
+// removeFiles removes the files at relPaths, relative to the home directory, from the docker filesystem.
+// Failures are only logged, it is meant for cleaning up temporary files.
+func (node *Node) removeFiles(ctx context.Context, relPaths ...string) {
+	cmd := []string{"rm", "-f"}
+	for _, relPath := range relPaths {
+		cmd = append(cmd, path.Join(node.HomeDir(), relPath))
+	}
+	if _, _, err := node.Exec(ctx, cmd, nil); err != nil {
+		node.logger().Info("Failed to remove files", zap.Strings("files", relPaths), zap.Error(err))
+	}
 }
 
 // CopyFile adds a file from the host filesystem to the docker filesystem
@@ -562,7 +1195,8 @@ func (node *Node) CopyFile(ctx context.Context, srcPath, dstPath string) error {
 // relPath describes the location of the file in the docker volume relative to the home directory.
 func (node *Node) ReadFile(ctx context.Context, relPath string) ([]byte, error) {
 	fr := dockerutil.NewFileRetriever(node.logger(), node.DockerClient, node.TestName)
-	gen, err := fr.SingleFileContent(ctx, node.VolumeName, node.Chain.Config().Name, relPath)
+	volumeName, chainName := node.homeVolume()
+	gen, err := fr.SingleFileContent(ctx, volumeName, chainName, relPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file at %s: %w", relPath, err)
 	}
@@ -582,6 +1216,61 @@ func (node *Node) CreateKey(ctx context.Context, name string) error {
 	return err
 }
 
+// CreateKeys creates a key in the keyring backend test for each of names and returns their addresses keyed by name.
+// Names which already exist in the keyring, or fail to be created, are reported in the returned error without
+// stopping the creation of the others; the addresses of the created keys are returned either way.
+func (node *Node) CreateKeys(ctx context.Context, names ...string) (map[string]string, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	stdout, _, err := node.ExecBin(ctx, "keys", "list", "--keyring-backend", keyring.BackendTest, "--output", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+	var existing []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(stdout, &existing); err != nil {
+		return nil, fmt.Errorf("failed to parse keys: %w", err)
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, key := range existing {
+		exists[key.Name] = true
+	}
+
+	addresses := make(map[string]string, len(names))
+	for _, name := range names {
+		if exists[name] {
+			err = multierr.Append(err, fmt.Errorf("key %q: already exists", name))
+			continue
+		}
+		stdout, stderr, addErr := node.ExecBin(ctx,
+			"keys", "add", name,
+			"--coin-type", node.Chain.Config().CoinType,
+			"--keyring-backend", keyring.BackendTest,
+			"--output", "json",
+		)
+		if addErr != nil {
+			err = multierr.Append(err, fmt.Errorf("key %q: %w", name, addErr))
+			continue
+		}
+
+		// Some SDK versions print the new key to stderr.
+		var key struct {
+			Address string `json:"address"`
+		}
+		if jsonErr := json.Unmarshal(stdout, &key); jsonErr != nil || key.Address == "" {
+			if jsonErr := json.Unmarshal(stderr, &key); jsonErr != nil {
+				err = multierr.Append(err, fmt.Errorf("key %q: failed to parse created key: %w", name, jsonErr))
+				continue
+			}
+		}
+		exists[name] = true
+		addresses[name] = key.Address
+	}
+	return addresses, err
+}
+
 // CreateHubKey creates a key in the keyring backend test for the given node
 func (node *Node) CreateHubKey(ctx context.Context, name string) error {
 	node.lock.Lock()
@@ -591,11 +1280,19 @@ func (node *Node) CreateHubKey(ctx context.Context, name string) error {
 		"keys", "add", name,
 		"--coin-type", node.Chain.Config().CoinType,
 		"--keyring-backend", keyring.BackendTest,
-		"--keyring-dir", keyDir+"/sequencer_keys",
+		"--keyring-dir", node.keyringDir(),
 	)
 	return err
 }
 
+// keyringDir returns the keyring directory of the hub key helpers.
+func (node *Node) keyringDir() string {
+	if node.KeyringDir != "" {
+		return node.KeyringDir
+	}
+	return node.HomeDir()
+}
+
 // RecoverKey restores a key from a given mnemonic.
 func (node *Node) RecoverKey(ctx context.Context, keyName, mnemonic string) error {
 	command := []string{
@@ -611,6 +1308,33 @@ func (node *Node) RecoverKey(ctx context.Context, keyName, mnemonic string) erro
 	return err
 }
 
+// SetupRelayerKey generates a mnemonic and recovers it as key name on every node, e.g. the nodes of both chain
+// ends of an IBC path, so the relayer can use the same key everywhere. It returns the mnemonic and the address
+// of the key on each chain, keyed by chain id, since addresses differ by bech32 prefix.
+func SetupRelayerKey(ctx context.Context, name string, nodes ...*Node) (mnemonic string, addresses map[string]string, err error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", nil, err
+	}
+	mnemonic, err = bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", nil, err
+	}
+
+	addresses = make(map[string]string, len(nodes))
+	for _, node := range nodes {
+		if err := node.RecoverKey(ctx, name, mnemonic); err != nil {
+			return "", nil, fmt.Errorf("failed to recover relayer key on %s: %w", node.Name(), err)
+		}
+		addr, err := node.AccountKeyBech32(ctx, name)
+		if err != nil {
+			return "", nil, err
+		}
+		addresses[node.Chain.Config().ChainID] = addr
+	}
+	return mnemonic, addresses, nil
+}
+
 func (node *Node) IsAboveSDK47(ctx context.Context) bool {
 	// In SDK v47, a new genesis core command was added. This spec has many state breaking features
 	// so we use this to switch between new and legacy SDK logic.
@@ -666,6 +1390,10 @@ func (node *Node) Gentx(ctx context.Context, name string, genesisSelfDelegation
 		"--keyring-backend", keyring.BackendTest,
 		"--chain-id", node.Chain.Config().ChainID)
 
+	if rate := node.Chain.Config().CommissionRate; rate != "" {
+		command = append(command, "--commission-rate", rate)
+	}
+
 	_, _, err := node.ExecBin(ctx, command...)
 	return err
 }
@@ -690,19 +1418,60 @@ func (node *Node) GentxSeq(ctx context.Context, keyName string) error {
 	return err
 }
 
-func (node *Node) RegisterRollAppToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, keyDir string) error {
+// RegisterRollAppToHub creates the rollapp on the hub. If initialSequencerAddr is set, the rollapp is created
+// permissioned with that address as its only allowed sequencer, so no other sequencer can register first.
+// initialSequencerAddr must belong to a hub key in the node's keyring directory.
+func (node *Node) RegisterRollAppToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, initialSequencerAddr string) error {
 	var command []string
-	detail := "{\"Addresses\":[]}"
-	keyPath := keyDir + "/sequencer_keys"
-	command = append(command, "rollapp", "create-rollapp", rollappChainID, maxSequencers, detail,
+	keyPath := node.keyringDir()
+
+	addresses := []string{}
+	if initialSequencerAddr != "" {
+		if err := node.assertHubKeyAddress(ctx, keyPath, initialSequencerAddr); err != nil {
+			return err
+		}
+		addresses = append(addresses, initialSequencerAddr)
+	}
+	detail, err := json.Marshal(map[string][]string{"Addresses": addresses})
+	if err != nil {
+		return err
+	}
+
+	command = append(command, "rollapp", "create-rollapp", rollappChainID, maxSequencers, string(detail),
 		"--broadcast-mode", "block", "--keyring-dir", keyPath)
-	_, err := node.ExecTx(ctx, keyName, command...)
+	_, err = node.ExecTx(ctx, keyName, command...)
 	return err
 }
 
-func (node *Node) RegisterSequencerToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, seq, keyDir string) error {
+// assertHubKeyAddress returns an error if address does not belong to a key in the keyring at keyPath.
+func (node *Node) assertHubKeyAddress(ctx context.Context, keyPath, address string) error {
+	stdout, _, err := node.ExecBin(ctx, "keys", "list",
+		"--keyring-backend", keyring.BackendTest,
+		"--keyring-dir", keyPath,
+		"--output", "json",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list hub keys: %w", err)
+	}
+
+	var keys []struct {
+		Name    string `json:"name"`
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(stdout, &keys); err != nil {
+		return fmt.Errorf("failed to decode hub keys: %w", err)
+	}
+	for _, k := range keys {
+		if k.Address == address {
+			return nil
+		}
+	}
+	return fmt.Errorf("sequencer address %s does not match any hub key in %s", address, keyPath)
+}
+
+func (node *Node) RegisterSequencerToHub(ctx context.Context, keyName, rollappChainID, maxSequencers, seq string) error {
 	var command []string
-	keyPath := keyDir + "/sequencer_keys"
+	keyPath := node.keyringDir()
 	command = append(command, "sequencer", "create-sequencer", seq, rollappChainID, "{\"Moniker\":\"myrollapp-sequencer\",\"Identity\":\"\",\"Website\":\"\",\"SecurityContact\":\"\",\"Details\":\"\"}",
 		"--broadcast-mode", "block", "--keyring-dir", keyPath)
 
@@ -774,6 +1543,24 @@ func (node *Node) SendFunds(ctx context.Context, keyName string, amount ibc.Wall
 	return err
 }
 
+// CreateFundedWallet creates a key with the given name, funds its address with amount from the funder key
+// and returns the bech32 address once the funding transaction is confirmed.
+func (node *Node) CreateFundedWallet(ctx context.Context, name string, funder string, amount types.Coins) (string, error) {
+	if err := node.CreateKey(ctx, name); err != nil {
+		return "", fmt.Errorf("failed to create key %q: %w", name, err)
+	}
+
+	address, err := node.AccountKeyBech32(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := node.ExecTx(ctx, funder, "bank", "send", funder, address, amount.String()); err != nil {
+		return "", fmt.Errorf("failed to fund %s from %q: %w", address, funder, err)
+	}
+	return address, nil
+}
+
 type InstantiateContractAttribute struct {
 	Value string `json:"value"`
 }
@@ -802,36 +1589,41 @@ type CodeInfosResponse struct {
 }
 
 // StoreContract takes a file path to smart contract and stores it on-chain. Returns the contracts code id.
+// Without extraExecTxArgs, a file with the same sha256 checksum as one stored before on this chain is not stored
+// again, the code id of the earlier upload is returned instead. Use StoreContractAndVerify to always store.
 func (node *Node) StoreContract(ctx context.Context, keyName string, fileName string, extraExecTxArgs ...string) (string, error) {
+	if chain, ok := node.Chain.(*CosmosChain); ok && len(extraExecTxArgs) == 0 {
+		return node.storeContractCached(ctx, chain, keyName, fileName)
+	}
+	codeID, _, err := node.StoreContractAndVerify(ctx, keyName, fileName, extraExecTxArgs...)
+	return codeID, err
+}
+
+// StoreContractAndVerify stores the smart contract at fileName on-chain and returns its code id and checksum,
+// read from the store_code event of the store tx so they identify exactly this upload among concurrent ones.
+func (node *Node) StoreContractAndVerify(ctx context.Context, keyName, fileName string, extraExecTxArgs ...string) (codeID string, codeHash string, err error) {
 	_, file := filepath.Split(fileName)
-	err := node.CopyFile(ctx, fileName, file)
-	if err != nil {
-		return "", fmt.Errorf("writing contract file to docker volume: %w", err)
+	if err := node.CopyFile(ctx, fileName, file); err != nil {
+		return "", "", fmt.Errorf("writing contract file to docker volume: %w", err)
 	}
 
 	cmd := []string{"wasm", "store", path.Join(node.HomeDir(), file), "--gas", "auto"}
 	cmd = append(cmd, extraExecTxArgs...)
 
-	if _, err := node.ExecTx(ctx, keyName, cmd...); err != nil {
-		return "", err
-	}
-
-	err = testutil.WaitForBlocks(ctx, 5, node.Chain)
+	txResp, err := node.ExecTxWithResponse(ctx, keyName, cmd...)
 	if err != nil {
-		return "", fmt.Errorf("wait for blocks: %w", err)
+		return "", "", err
 	}
-
-	stdout, _, err := node.ExecQuery(ctx, "wasm", "list-code", "--reverse")
-	if err != nil {
-		return "", err
+	if txResp.Code != 0 {
+		return "", "", fmt.Errorf("error in transaction (code: %d): %s", txResp.Code, txResp.RawLog)
 	}
 
-	res := CodeInfosResponse{}
-	if err := json.Unmarshal([]byte(stdout), &res); err != nil {
-		return "", err
+	codeID, ok := AttributeValue(txResp.Events, "store_code", "code_id")
+	if !ok {
+		return "", "", fmt.Errorf("tx %s has no store_code event with a code_id", txResp.TxHash)
 	}
-
-	return res.CodeInfos[0].CodeID, nil
+	codeHash, _ = AttributeValue(txResp.Events, "store_code", "code_checksum")
+	return codeID, codeHash, nil
 }
 
 func (node *Node) GetTransaction(clientCtx client.Context, txHash string) (*types.TxResponse, error) {
@@ -851,6 +1643,28 @@ func (node *Node) GetTransaction(clientCtx client.Context, txHash string) (*type
 	return txResp, err
 }
 
+// TxPaidFee returns the fee paid by the transaction with the given hash, as declared in its auth info.
+// This is the exact amount deducted from the fee payer, unlike a balance delta which other activity can affect.
+func (node *Node) TxPaidFee(ctx context.Context, txHash string) (types.Coins, error) {
+	txResp, err := node.GetTransaction(node.CliContext(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+	if txResp.Tx == nil {
+		return nil, fmt.Errorf("transaction %s has no body", txHash)
+	}
+
+	var sdkTx types.Tx
+	if err := node.Chain.Config().EncodingConfig.InterfaceRegistry.UnpackAny(txResp.Tx, &sdkTx); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction %s: %w", txHash, err)
+	}
+	feeTx, ok := sdkTx.(types.FeeTx)
+	if !ok {
+		return nil, fmt.Errorf("transaction %s of type %T does not carry a fee", txHash, sdkTx)
+	}
+	return feeTx.GetFee(), nil
+}
+
 // HasCommand checks if a command in the chain binary is available.
 func (node *Node) HasCommand(ctx context.Context, command ...string) bool {
 	_, _, err := node.ExecBin(ctx, command...)
@@ -946,6 +1760,20 @@ func (node *Node) GetBuildInformation(ctx context.Context) *BinaryBuildInformati
 	}
 }
 
+// AssertBinaryVersion returns an error if the version reported by the chain binary does not match expectedVersion.
+// This guards against a stale image being picked up when running a multi-version test matrix.
+func (node *Node) AssertBinaryVersion(ctx context.Context, expectedVersion string) error {
+	info := node.GetBuildInformation(ctx)
+	if info == nil {
+		return fmt.Errorf("failed to get build information from %s on node %s", node.Chain.Config().Bin, node.Name())
+	}
+	if info.Version != expectedVersion {
+		return fmt.Errorf("binary %s on node %s (image %s) has version %q (commit %s), expected %q",
+			node.Chain.Config().Bin, node.Name(), node.Image.Ref(), info.Version, info.Commit, expectedVersion)
+	}
+	return nil
+}
+
 // InstantiateContract takes a code id for a smart contract and initialization message and returns the instantiated contract address.
 func (node *Node) InstantiateContract(ctx context.Context, keyName string, codeID string, initMessage string, needsNoAdminFlag bool, extraExecTxArgs ...string) (string, error) {
 	command := []string{"wasm", "instantiate", codeID, initMessage, "--label", "wasm-contract"}
@@ -985,16 +1813,11 @@ func (node *Node) ExecuteContract(ctx context.Context, keyName string, contractA
 	cmd := []string{"wasm", "execute", contractAddress, message}
 	cmd = append(cmd, extraExecTxArgs...)
 
-	txHash, err := node.ExecTx(ctx, keyName, cmd...)
+	txResp, err := node.ExecTxWithResponse(ctx, keyName, cmd...)
 	if err != nil {
 		return &types.TxResponse{}, err
 	}
 
-	txResp, err := node.GetTransaction(node.CliContext(), txHash)
-	if err != nil {
-		return &types.TxResponse{}, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
-	}
-
 	if txResp.Code != 0 {
 		return txResp, fmt.Errorf("error in transaction (code: %d): %s", txResp.Code, txResp.RawLog)
 	}
@@ -1002,6 +1825,64 @@ func (node *Node) ExecuteContract(ctx context.Context, keyName string, contractA
 	return txResp, nil
 }
 
+// MigrateContract migrates the contract at contractAddr to the code newCodeID, passing it migrateMsg.
+// keyName must be the admin of the contract, so the contract must have been instantiated with an admin.
+func (node *Node) MigrateContract(ctx context.Context, keyName, contractAddr, newCodeID, migrateMsg string, extraExecTxArgs ...string) (*types.TxResponse, error) {
+	cmd := []string{"wasm", "migrate", contractAddr, newCodeID, migrateMsg}
+	cmd = append(cmd, extraExecTxArgs...)
+
+	txResp, err := node.ExecTxWithResponse(ctx, keyName, cmd...)
+	if err == nil && txResp.Code != 0 {
+		err = fmt.Errorf("error in transaction (code: %d): %s", txResp.Code, txResp.RawLog)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "unauthorized") {
+			return txResp, fmt.Errorf("migrating contract %s requires %s to be its admin, was it instantiated without an admin? %w", contractAddr, keyName, err)
+		}
+		return txResp, err
+	}
+	return txResp, nil
+}
+
+// UpdateContractAdmin sets the admin of the contract to newAdmin. keyName must be the current admin.
+func (node *Node) UpdateContractAdmin(ctx context.Context, keyName, contractAddr, newAdmin string) (string, error) {
+	return node.ExecTx(ctx, keyName, "wasm", "set-contract-admin", contractAddr, newAdmin)
+}
+
+// ClearContractAdmin removes the admin of the contract, which can't be migrated anymore afterwards.
+// keyName must be the current admin.
+func (node *Node) ClearContractAdmin(ctx context.Context, keyName, contractAddr string) (string, error) {
+	return node.ExecTx(ctx, keyName, "wasm", "clear-contract-admin", contractAddr)
+}
+
+// QueryContractInfo returns the metadata of the contract, including its admin, which is empty if it has none.
+func (node *Node) QueryContractInfo(ctx context.Context, contractAddr string) (*ContractInfo, error) {
+	stdout, _, err := node.ExecQuery(ctx, "wasm", "contract", contractAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		ContractInfo ContractInfo `json:"contract_info"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return nil, err
+	}
+	return &res.ContractInfo, nil
+}
+
+// AssertContractGasUnder executes a contract message and returns an error if the transaction used more than maxGas.
+func (node *Node) AssertContractGasUnder(ctx context.Context, keyName, contractAddress, message string, maxGas uint64) error {
+	txResp, err := node.ExecuteContract(ctx, keyName, contractAddress, message, "--gas", "auto")
+	if err != nil {
+		return err
+	}
+	if txResp.GasUsed < 0 || uint64(txResp.GasUsed) > maxGas {
+		return fmt.Errorf("contract %s execution used %d gas, exceeding limit %d (tx %s)", contractAddress, txResp.GasUsed, maxGas, txResp.TxHash)
+	}
+	return nil
+}
+
 // QueryContract performs a smart query, taking in a query struct and returning a error with the response struct populated.
 func (node *Node) QueryContract(ctx context.Context, contractAddress string, queryMsg any, response any) error {
 	var query []byte
@@ -1124,8 +2005,7 @@ func (node *Node) SubmitProposal(ctx context.Context, keyName string, prop TxPro
 	if err != nil {
 		return "", err
 	}
-	fw := dockerutil.NewFileWriter(node.logger(), node.DockerClient, node.TestName)
-	if err := fw.WriteFile(ctx, node.VolumeName, node.Chain.Config().Name, file, propJson); err != nil {
+	if err := node.WriteFile(ctx, propJson, file); err != nil {
 		return "", fmt.Errorf("writing contract file to docker volume: %w", err)
 	}
 
@@ -1223,6 +2103,25 @@ func (node *Node) QueryBankMetadata(ctx context.Context, denom string) (*BankMet
 	return &meta, nil
 }
 
+// WaitForDenomMetadata polls the bank denom metadata of denom until it is registered, e.g. by metadata
+// forwarding middleware after an IBC transfer, and returns it.
+func (node *Node) WaitForDenomMetadata(ctx context.Context, denom string, timeout time.Duration) (*BankMetaData, error) {
+	var meta *BankMetaData
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		// The query fails until the metadata exists, so errors only mean "not yet".
+		res, err := node.QueryBankMetadata(ctx, denom)
+		if err != nil || res.Metadata.Base == "" {
+			return false, nil
+		}
+		meta = res
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("denom metadata for %s not found: %w", denom, err)
+	}
+	return meta, nil
+}
+
 func (node *Node) ExportState(ctx context.Context, height int64) (string, error) {
 	node.lock.Lock()
 	defer node.lock.Unlock()
@@ -1270,7 +2169,56 @@ func (node *Node) UnsafeResetAll(ctx context.Context) error {
 	return err
 }
 
+// ListSnapshots returns the state sync snapshots stored by the node.
+// The node must be stopped since the command opens the application database.
+func (node *Node) ListSnapshots(ctx context.Context) ([]Snapshot, error) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	stdout, stderr, err := node.ExecBin(ctx, "snapshots", "list")
+	if err != nil {
+		return nil, err
+	}
+
+	// output comes to stderr on some versions
+	var snapshots []Snapshot
+	for _, line := range strings.Split(string(stdout)+string(stderr), "\n") {
+		var s Snapshot
+		if _, err := fmt.Sscanf(strings.TrimSpace(line), "height: %d, format: %d, chunks: %d", &s.Height, &s.Format, &s.Chunks); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
+
+// ExportSnapshot takes a snapshot of the application state at the latest height, or at height if it is non-zero.
+// The node must be stopped since the command opens the application database.
+func (node *Node) ExportSnapshot(ctx context.Context, height uint64) error {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	command := []string{"snapshots", "export"}
+	if height > 0 {
+		command = append(command, "--height", strconv.FormatUint(height, 10))
+	}
+	_, _, err := node.ExecBin(ctx, command...)
+	return err
+}
+
+// RestoreSnapshot restores the application state from the local snapshot with the given height and format.
+// The node must be stopped since the command opens the application database.
+func (node *Node) RestoreSnapshot(ctx context.Context, height uint64, format uint32) error {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+
+	_, _, err := node.ExecBin(ctx, "snapshots", "restore", strconv.FormatUint(height, 10), strconv.FormatUint(uint64(format), 10))
+	return err
+}
+
 func (node *Node) CreateNodeContainer(ctx context.Context) error {
+	defer node.recordStartupTiming(time.Now(), func(t *StartupTimings, d time.Duration) { t.CreateNodeContainer = d })
+
 	chainCfg := node.Chain.Config()
 
 	var cmd []string
@@ -1282,10 +2230,63 @@ func (node *Node) CreateNodeContainer(ctx context.Context) error {
 	if chainCfg.Type == "rollapp" {
 		cmd = []string{chainCfg.Bin, "start", "--home", node.HomeDir()}
 	}
-	return node.containerLifecycle.CreateContainer(ctx, node.TestName, node.NetworkID, node.Image, sentryPorts, node.Bind(), node.HostName(), cmd, nil)
+	return node.containerLifecycle.CreateContainer(ctx, node.TestName, node.NetworkID, node.Image, sentryPorts, node.Bind(), node.HostName(), cmd, node.containerEnv(), node.containerResources())
+}
+
+// containerEnv returns the environment variables of the node container.
+func (node *Node) containerEnv() []string {
+	var env []string
+	if node.GOGC != "" {
+		env = append(env, "GOGC="+node.GOGC)
+	}
+	if node.GOMEMLIMIT != "" {
+		env = append(env, "GOMEMLIMIT="+node.GOMEMLIMIT)
+	}
+	return env
+}
+
+// containerResources returns the resource constraints of the node container.
+func (node *Node) containerResources() *container.Resources {
+	return &container.Resources{
+		CPUShares: node.CPUShares,
+		Memory:    node.MemoryBytes,
+	}
+}
+
+// StreamLogs follows the stdout and stderr output of the node container, starting with its full history,
+// e.g. to tee it into the test log. The stream ends when the container stops or ctx is cancelled.
+// The caller must close the returned reader.
+func (node *Node) StreamLogs(ctx context.Context) (io.ReadCloser, error) {
+	return node.containerLifecycle.StreamLogs(ctx)
+}
+
+// DumpLogs copies the full stdout and stderr output of the node container to w, e.g. on test failure.
+func (node *Node) DumpLogs(ctx context.Context, w io.Writer) error {
+	logs, err := node.containerLifecycle.Logs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read logs of container %s: %w", node.Name(), err)
+	}
+	_, err = w.Write(logs)
+	return err
+}
+
+// MemoryStats returns the current memory usage of the node container.
+func (node *Node) MemoryStats(ctx context.Context) (*ContainerMemStats, error) {
+	stats, err := node.containerLifecycle.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	return &ContainerMemStats{
+		Usage:    stats.MemoryStats.Usage,
+		MaxUsage: stats.MemoryStats.MaxUsage,
+		Limit:    stats.MemoryStats.Limit,
+		Failcnt:  stats.MemoryStats.Failcnt,
+	}, nil
 }
 
 func (node *Node) StartContainer(ctx context.Context) error {
+	defer node.recordStartupTiming(time.Now(), func(t *StartupTimings, d time.Duration) { t.StartContainer = d })
+
 	if err := node.containerLifecycle.StartContainer(ctx); err != nil {
 		return err
 	}
@@ -1316,6 +2317,62 @@ func (node *Node) StartContainer(ctx context.Context) error {
 	}, retry.Context(ctx), retry.Attempts(40), retry.Delay(3*time.Second), retry.DelayType(retry.FixedDelay))
 }
 
+// WaitForExit waits up to timeout for the node container to exit and returns its exit code,
+// e.g. to assert on the exit code of an upgrade halt. The reason docker gives for the exit, if any, is logged.
+func (node *Node) WaitForExit(ctx context.Context, timeout time.Duration) (exitCode int, err error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if _, err := node.containerLifecycle.WaitForExit(waitCtx); err != nil {
+		if waitCtx.Err() != nil {
+			return -1, fmt.Errorf("node %s still running after %s", node.Name(), timeout)
+		}
+		return -1, fmt.Errorf("waiting for container %s to exit: %w", node.Name(), err)
+	}
+
+	exitCode, reason, err := node.containerLifecycle.ExitState(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("failed to inspect container %s: %w", node.Name(), err)
+	}
+	if reason != "" {
+		node.logger().Info("Container exited", zap.Int("exit_code", exitCode), zap.String("reason", reason))
+	}
+	return exitCode, nil
+}
+
+// StartExpectingFailure creates and starts the node container, expecting the node process to exit
+// within timeout, e.g. because of a malformed genesis. It returns as soon as the container exits,
+// along with the container logs so the caller can assert on the failure reason.
+// An error is returned if the node is still running after timeout or exits with code 0.
+func (node *Node) StartExpectingFailure(ctx context.Context, timeout time.Duration) (string, error) {
+	if err := node.CreateNodeContainer(ctx); err != nil {
+		return "", err
+	}
+	if err := node.containerLifecycle.StartContainer(ctx); err != nil {
+		return "", err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	exitCode, waitErr := node.containerLifecycle.WaitForExit(waitCtx)
+
+	logs, err := node.containerLifecycle.Logs(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs of container %s: %w", node.Name(), err)
+	}
+
+	if waitErr != nil {
+		if waitCtx.Err() != nil {
+			return string(logs), fmt.Errorf("node %s still running after %s, expected it to fail on startup", node.Name(), timeout)
+		}
+		return string(logs), fmt.Errorf("waiting for container %s to exit: %w", node.Name(), waitErr)
+	}
+	if exitCode == 0 {
+		return string(logs), fmt.Errorf("node %s exited with code 0, expected it to fail on startup", node.Name())
+	}
+
+	return string(logs), nil
+}
+
 func (node *Node) PauseContainer(ctx context.Context) error {
 	return node.containerLifecycle.PauseContainer(ctx)
 }
@@ -1328,10 +2385,65 @@ func (node *Node) StopContainer(ctx context.Context) error {
 	return node.containerLifecycle.StopContainer(ctx)
 }
 
+// RestartContainer stops and starts the node container, keeping its volume and thus its state.
+// Like StartContainer, it re-reads the host ports, replaces the RPC client and returns once the node caught up.
+func (node *Node) RestartContainer(ctx context.Context) error {
+	if err := node.StopContainer(ctx); err != nil {
+		return fmt.Errorf("failed to stop node: %w", err)
+	}
+	if err := node.StartContainer(ctx); err != nil {
+		return fmt.Errorf("failed to restart node: %w", err)
+	}
+	return nil
+}
+
 func (node *Node) RemoveContainer(ctx context.Context) error {
 	return node.containerLifecycle.RemoveContainer(ctx)
 }
 
+// walCorruptBytes is the number of bytes CorruptWAL cuts from the end of the consensus WAL.
+const walCorruptBytes = 16
+
+// CorruptWAL stops the node, truncates the tail of its consensus write-ahead log as a crash mid-write would,
+// restarts it and returns an error unless the node recovers and produces or syncs new blocks.
+func (node *Node) CorruptWAL(ctx context.Context) error {
+	const walPath = "data/cs.wal/wal"
+
+	height, err := node.Height(ctx)
+	if err != nil {
+		return err
+	}
+	if err := node.StopContainer(ctx); err != nil {
+		return fmt.Errorf("failed to stop node: %w", err)
+	}
+
+	wal, err := node.ReadFile(ctx, walPath)
+	if err != nil {
+		return err
+	}
+	if len(wal) <= walCorruptBytes {
+		return fmt.Errorf("wal is too small to corrupt: %d bytes", len(wal))
+	}
+	if err := node.WriteFile(ctx, wal[:len(wal)-walCorruptBytes], walPath); err != nil {
+		return fmt.Errorf("failed to write truncated wal: %w", err)
+	}
+
+	if err := node.StartContainer(ctx); err != nil {
+		return fmt.Errorf("node did not restart after wal corruption: %w", err)
+	}
+	err = testutil.WaitForCondition(time.Minute, blockTime*time.Second, func() (bool, error) {
+		h, err := node.Height(ctx)
+		if err != nil {
+			return false, nil
+		}
+		return h > height, nil
+	})
+	if err != nil {
+		return fmt.Errorf("node did not resume after wal corruption: %w", err)
+	}
+	return nil
+}
+
 // InitValidatorFiles creates the node files and signs a genesis transaction
 func (node *Node) InitValidatorGenTx(
 	ctx context.Context,
@@ -1360,6 +2472,8 @@ func (node *Node) InitValidatorGenTx(
 }
 
 func (node *Node) InitFullNodeFiles(ctx context.Context) error {
+	defer node.recordStartupTiming(time.Now(), func(t *StartupTimings, d time.Duration) { t.InitFullNodeFiles = d })
+
 	if err := node.InitHomeFolder(ctx); err != nil {
 		return err
 	}
@@ -1367,6 +2481,20 @@ func (node *Node) InitFullNodeFiles(ctx context.Context) error {
 	return node.SetTestConfig(ctx)
 }
 
+// StartupTimings returns how long the node spent in each setup step.
+// Steps which have not run yet report a zero duration.
+func (node *Node) StartupTimings() StartupTimings {
+	node.timingsMu.Lock()
+	defer node.timingsMu.Unlock()
+	return node.startupTimings
+}
+
+func (node *Node) recordStartupTiming(start time.Time, set func(*StartupTimings, time.Duration)) {
+	node.timingsMu.Lock()
+	defer node.timingsMu.Unlock()
+	set(&node.startupTimings, time.Since(start))
+}
+
 // NodeID returns the persistent ID of a given node.
 func (node *Node) NodeID(ctx context.Context) (string, error) {
 	// This used to call p2p.LoadNodeKey against the file on the host,
@@ -1411,7 +2539,7 @@ func (node *Node) HubKeyBech32(ctx context.Context, name string, bech string) (s
 	command := []string{node.Chain.Config().Bin, "keys", "show", "--address", name,
 		"--home", node.HomeDir(),
 		"--keyring-backend", keyring.BackendTest,
-		"--keyring-dir", keyDir + "/sequencer_keys",
+		"--keyring-dir", node.keyringDir(),
 	}
 
 	if bech != "" {
@@ -1469,6 +2597,133 @@ func (nodes Nodes) LogGenesisHashes(ctx context.Context) error {
 	return nil
 }
 
+// AssertConvergence waits until the heights of all nodes are within `within` blocks of each other
+// and the network keeps advancing past the height at which they converged, e.g. after a partition heals.
+func (nodes Nodes) AssertConvergence(ctx context.Context, within uint64, timeout time.Duration) error {
+	var convergedAt uint64
+	err := testutil.WaitForCondition(timeout, blockTime*time.Second, func() (bool, error) {
+		var lowest, highest uint64
+		for i, n := range nodes {
+			// A node which is still recovering may not answer yet.
+			h, err := n.Height(ctx)
+			if err != nil {
+				return false, nil
+			}
+			if i == 0 || h < lowest {
+				lowest = h
+			}
+			if h > highest {
+				highest = h
+			}
+		}
+		if highest-lowest > within {
+			convergedAt = 0
+			return false, nil
+		}
+		if convergedAt == 0 {
+			convergedAt = highest
+			return false, nil
+		}
+		return lowest > convergedAt, nil
+	})
+	if err != nil {
+		return fmt.Errorf("nodes did not converge within %d blocks: %w", within, err)
+	}
+	return nil
+}
+
+// AssertBlockResultsMatch compares the block results of every validator at height with those of the first
+// validator and describes the first difference in the returned error. Diverging results at the same height
+// point at non-deterministic state machine code.
+func (nodes Nodes) AssertBlockResultsMatch(ctx context.Context, height int64) error {
+	var validators Nodes
+	for _, n := range nodes {
+		if n.Validator {
+			validators = append(validators, n)
+		}
+	}
+	if len(validators) < 2 {
+		return fmt.Errorf("need at least 2 validators to compare block results, got %d", len(validators))
+	}
+
+	ref, err := validators[0].Client.BlockResults(ctx, &height)
+	if err != nil {
+		return fmt.Errorf("failed to get block results of %s at height %d: %w", validators[0].Name(), height, err)
+	}
+	for _, n := range validators[1:] {
+		res, err := n.Client.BlockResults(ctx, &height)
+		if err != nil {
+			return fmt.Errorf("failed to get block results of %s at height %d: %w", n.Name(), height, err)
+		}
+		if diff := diffBlockResults(ref, res); diff != "" {
+			return fmt.Errorf("block results at height %d differ between %s and %s: %s", height, validators[0].Name(), n.Name(), diff)
+		}
+	}
+	return nil
+}
+
+// diffBlockResults describes the first difference between a and b, or returns an empty string if they match.
+func diffBlockResults(a, b *coretypes.ResultBlockResults) string {
+	if len(a.TxsResults) != len(b.TxsResults) {
+		return fmt.Sprintf("tx count %d != %d", len(a.TxsResults), len(b.TxsResults))
+	}
+	for i := range a.TxsResults {
+		ta, tb := a.TxsResults[i], b.TxsResults[i]
+		switch {
+		case ta.Code != tb.Code:
+			return fmt.Sprintf("tx %d: code %d != %d", i, ta.Code, tb.Code)
+		case ta.GasUsed != tb.GasUsed:
+			return fmt.Sprintf("tx %d: gas used %d != %d", i, ta.GasUsed, tb.GasUsed)
+		case ta.GasWanted != tb.GasWanted:
+			return fmt.Sprintf("tx %d: gas wanted %d != %d", i, ta.GasWanted, tb.GasWanted)
+		case !bytes.Equal(ta.Data, tb.Data):
+			return fmt.Sprintf("tx %d: data %X != %X", i, ta.Data, tb.Data)
+		}
+		if diff := diffEvents(ta.Events, tb.Events); diff != "" {
+			return fmt.Sprintf("tx %d: %s", i, diff)
+		}
+	}
+	if diff := diffEvents(a.FinalizeBlockEvents, b.FinalizeBlockEvents); diff != "" {
+		return "finalize block " + diff
+	}
+	if !bytes.Equal(a.AppHash, b.AppHash) {
+		return fmt.Sprintf("app hash %X != %X", a.AppHash, b.AppHash)
+	}
+	return ""
+}
+
+func diffEvents(a, b []abcitypes.Event) string {
+	if len(a) != len(b) {
+		return fmt.Sprintf("event count %d != %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type {
+			return fmt.Sprintf("event %d: type %q != %q", i, a[i].Type, b[i].Type)
+		}
+		if len(a[i].Attributes) != len(b[i].Attributes) {
+			return fmt.Sprintf("event %d (%s): attribute count %d != %d", i, a[i].Type, len(a[i].Attributes), len(b[i].Attributes))
+		}
+		for j := range a[i].Attributes {
+			aa, ba := a[i].Attributes[j], b[i].Attributes[j]
+			if aa.Key != ba.Key || aa.Value != ba.Value {
+				return fmt.Sprintf("event %d (%s): attribute %s=%q != %s=%q", i, a[i].Type, aa.Key, aa.Value, ba.Key, ba.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// AssertUniqueHomeDirs claims the home dir of each of the nodes and returns an error if any of them is already
+// claimed by another node of this process, of this or another chain, e.g. of a parallel test on the same docker host.
+// Default home dirs include the unique volume name of the node, so collisions come from SetHomeSubdir overrides.
+func (nodes Nodes) AssertUniqueHomeDirs() error {
+	var err error
+	for _, n := range nodes {
+		err = multierr.Append(err, n.claimHomeDir())
+	}
+	return err
+}
+
 func (nodes Nodes) logger() *zap.Logger {
 	if len(nodes) == 0 {
 		return zap.NewNop()
@@ -1486,6 +2741,12 @@ func (node *Node) Exec(ctx context.Context, cmd []string, env []string) ([]byte,
 	return res.Stdout, res.Stderr, res.Err
 }
 
+// ExecInContainer runs cmd inside the node's running container, unlike Exec which runs it in a fresh container,
+// e.g. to inspect live files or signal the node process.
+func (node *Node) ExecInContainer(ctx context.Context, cmd []string) ([]byte, []byte, error) {
+	return node.containerLifecycle.Exec(ctx, cmd, nil)
+}
+
 func (node *Node) logger() *zap.Logger {
 	return node.log.With(
 		zap.String("chain_id", node.Chain.Config().ChainID),
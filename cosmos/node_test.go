@@ -0,0 +1,42 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/decentrio/rollup-e2e-testing/ibc"
+)
+
+func TestAssertUniqueHomeDirs(t *testing.T) {
+	t.Parallel()
+
+	log := zap.NewNop()
+	hub := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "hub", Name: "hub"}, 1, 0, log)
+	rollapp := NewCosmosChain(t.Name(), ibc.ChainConfig{ChainID: "rollapp", Name: "rollapp"}, 1, 0, log)
+
+	hubVal := NewNode(log, true, hub, nil, "", t.Name(), ibc.DockerImage{}, 0)
+	hubVal.VolumeName = "vol-a"
+	rollappVal := NewNode(log, true, rollapp, nil, "", t.Name(), ibc.DockerImage{}, 0)
+	rollappVal.VolumeName = "vol-b"
+
+	require.NoError(t, Nodes{hubVal}.AssertUniqueHomeDirs())
+	require.NoError(t, Nodes{rollappVal}.AssertUniqueHomeDirs())
+	// Claiming again is a no-op.
+	require.NoError(t, Nodes{hubVal, rollappVal}.AssertUniqueHomeDirs())
+
+	// The rollapp node overrides its home dir with the one of the hub node, across chains.
+	rollappVal.homeSubdir = "hub" + hubVal.VolumeName
+	err := Nodes{rollappVal}.AssertUniqueHomeDirs()
+	require.ErrorContains(t, err, "share home dir /var/cosmos-chain/hubvol-a")
+
+	// Once released, the home dir can be claimed by another node.
+	hubVal.releaseHomeDir()
+	require.NoError(t, Nodes{rollappVal}.AssertUniqueHomeDirs())
+
+	// Claims are scoped to the test, a node of another test may use the same home dir.
+	otherVal := NewNode(log, true, hub, nil, "", t.Name()+"-other", ibc.DockerImage{}, 0)
+	otherVal.VolumeName = "vol-a"
+	require.NoError(t, Nodes{otherVal}.AssertUniqueHomeDirs())
+}
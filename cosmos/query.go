@@ -3,10 +3,13 @@ package cosmos
 import (
 	"context"
 	"fmt"
+	"time"
 
+	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	tmtypes "github.com/cometbft/cometbft/rpc/core/types"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
 )
 
 type blockClient interface {
@@ -34,3 +37,135 @@ func RangeBlockMessages(ctx context.Context, interfaceRegistry codectypes.Interf
 	}
 	return nil
 }
+
+// FindMessagesByType returns the decoded messages with the given type url, e.g. "/cosmos.bank.v1beta1.MsgSend",
+// from all txs in blocks startHeight through endHeight, in block order. Heights before the earliest block of the node
+// are skipped, so 1 scans from the start of a chain with a genesis initial height.
+func (node *Node) FindMessagesByType(ctx context.Context, startHeight, endHeight uint64, msgTypeURL string) ([]sdk.Msg, error) {
+	startHeight, err := node.clampStartHeight(ctx, startHeight)
+	if err != nil {
+		return nil, err
+	}
+	interfaceRegistry := node.Chain.Config().EncodingConfig.InterfaceRegistry
+	var msgs []sdk.Msg
+	for h := startHeight; h <= endHeight; h++ {
+		err := RangeBlockMessages(ctx, interfaceRegistry, node.Client, h, func(msg sdk.Msg) bool {
+			if sdk.MsgTypeURL(msg) == msgTypeURL {
+				msgs = append(msgs, msg)
+			}
+			return false
+		})
+		if err != nil {
+			return nil, fmt.Errorf("height %d: %w", h, err)
+		}
+	}
+	return msgs, nil
+}
+
+// ActivitySummary aggregates the txs of blocks startHeight through endHeight: the number of txs,
+// the number of messages of each type, the total gas used and the total fees paid.
+// Heights before the earliest block of the node are skipped, like in FindMessagesByType.
+func (node *Node) ActivitySummary(ctx context.Context, startHeight, endHeight uint64) (*ActivitySummary, error) {
+	startHeight, err := node.clampStartHeight(ctx, startHeight)
+	if err != nil {
+		return nil, err
+	}
+	interfaceRegistry := node.Chain.Config().EncodingConfig.InterfaceRegistry
+	summary := &ActivitySummary{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+		MsgCounts:   make(map[string]int),
+		TotalFees:   sdk.NewCoins(),
+	}
+	for h := startHeight; h <= endHeight; h++ {
+		height := int64(h)
+		block, err := node.Client.Block(ctx, &height)
+		if err != nil {
+			return nil, fmt.Errorf("tendermint rpc get block: %w", err)
+		}
+		blockRes, err := node.Client.BlockResults(ctx, &height)
+		if err != nil {
+			return nil, fmt.Errorf("tendermint rpc get block results: %w", err)
+		}
+		if len(blockRes.TxsResults) != len(block.Block.Txs) {
+			return nil, fmt.Errorf("block %d has %d txs but %d tx results", h, len(block.Block.Txs), len(blockRes.TxsResults))
+		}
+
+		for i, txbz := range block.Block.Txs {
+			summary.TotalTxs++
+			summary.TotalGasUsed += blockRes.TxsResults[i].GasUsed
+
+			tx, err := decodeTX(interfaceRegistry, txbz)
+			if err != nil {
+				return nil, fmt.Errorf("decode tendermint tx: %w", err)
+			}
+			for _, msg := range tx.GetMsgs() {
+				summary.MsgCounts[sdk.MsgTypeURL(msg)]++
+			}
+			if feeTx, ok := tx.(sdk.FeeTx); ok {
+				summary.TotalFees = summary.TotalFees.Add(feeTx.GetFee()...)
+			}
+		}
+	}
+	return summary, nil
+}
+
+// clampStartHeight raises startHeight to the earliest block of the node.
+func (node *Node) clampStartHeight(ctx context.Context, startHeight uint64) (uint64, error) {
+	earliest, err := node.EarliestHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if startHeight < earliest {
+		return earliest, nil
+	}
+	return startHeight, nil
+}
+
+// proofHeaderTimeout bounds the wait in QueryWithProof for the block committing the app hash of the queried height.
+const proofHeaderTimeout = time.Minute
+
+// QueryWithProof queries key from the store storeKey, e.g. "ibc", at height, or the latest height if 0,
+// and returns its value along with the proto encoded ics23 merkle proof of its inclusion, or of its
+// exclusion if value is empty. The proof is verified against the app hash of the block at height+1,
+// which is waited for if not committed yet.
+func (node *Node) QueryWithProof(ctx context.Context, storeKey string, key []byte, height int64) (value []byte, proof []byte, err error) {
+	res, err := node.Client.ABCIQueryWithOptions(ctx, fmt.Sprintf("store/%s/key", storeKey), key,
+		rpcclient.ABCIQueryOptions{Height: height, Prove: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query %s store with proof: %w", storeKey, err)
+	}
+	if !res.Response.IsOK() {
+		return nil, nil, fmt.Errorf("query of %s store failed with code %d: %s", storeKey, res.Response.Code, res.Response.Log)
+	}
+
+	merkleProof, err := commitmenttypes.ConvertProofs(res.Response.ProofOps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert proof: %w", err)
+	}
+
+	// The app hash of the state at a height is committed in the header of the next block.
+	headerHeight := res.Response.Height + 1
+	if err := node.WaitForHeightWithTimeout(ctx, uint64(headerHeight), proofHeaderTimeout); err != nil {
+		return nil, nil, err
+	}
+	block, err := node.Client.Block(ctx, &headerHeight)
+	if err != nil {
+		return nil, nil, fmt.Errorf("tendermint rpc get block: %w", err)
+	}
+	root := commitmenttypes.NewMerkleRoot(block.Block.AppHash)
+	path := commitmenttypes.NewMerklePath(storeKey, string(key))
+	if len(res.Response.Value) == 0 {
+		err = merkleProof.VerifyNonMembership(commitmenttypes.GetSDKSpecs(), root, path)
+	} else {
+		err = merkleProof.VerifyMembership(commitmenttypes.GetSDKSpecs(), root, path, res.Response.Value)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("proof of key %x in %s store at height %d does not verify: %w", key, storeKey, res.Response.Height, err)
+	}
+	proof, err = node.Chain.Config().EncodingConfig.Codec.Marshal(&merkleProof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal proof: %w", err)
+	}
+	return res.Response.Value, proof, nil
+}
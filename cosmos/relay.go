@@ -0,0 +1,487 @@
+package cosmos
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"cosmossdk.io/math"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/types"
+	transfertypes "github.com/cosmos/ibc-go/v8/modules/apps/transfer/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	chantypes "github.com/cosmos/ibc-go/v8/modules/core/04-channel/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+)
+
+// manualRelayGasLimit is the gas limit of the client update and packet receive tx built by ManualRelayPacket.
+const manualRelayGasLimit = 2_000_000
+
+// ManualRelayPacket relays the packet sent by src on srcPortID/srcChannelID with the given sequence to this node,
+// without a relayer. It updates this chain's light client of src to a height proving the packet commitment and
+// submits a MsgRecvPacket in the same tx, signed and paid for by destKey.
+// The light client must still be within its trusting period.
+func (node *Node) ManualRelayPacket(ctx context.Context, src *Node, srcPortID, srcChannelID string, sequence uint64, destKey string) (*types.TxResponse, error) {
+	updateMsg, recvMsg, err := node.recvPacketMsgs(ctx, src, srcPortID, srcChannelID, sequence, destKey)
+	if err != nil {
+		return nil, err
+	}
+	return node.relayMsgs(ctx, destKey, updateMsg, recvMsg)
+}
+
+// AssertRecvPacketIdempotent relays the transfer packet sent by src on srcPortID/srcChannelID with the given sequence
+// to this node like ManualRelayPacket, then submits the same MsgRecvPacket again and returns an error unless the
+// second submission is rejected as redundant or leaves the receiver's voucher balance unchanged.
+// destKey should not be the receiver of the packet, so that fees don't blur the balance check.
+func (node *Node) AssertRecvPacketIdempotent(ctx context.Context, src *Node, srcPortID, srcChannelID string, sequence uint64, destKey string) error {
+	updateMsg, recvMsg, err := node.recvPacketMsgs(ctx, src, srcPortID, srcChannelID, sequence, destKey)
+	if err != nil {
+		return err
+	}
+	packet := recvMsg.Packet
+
+	var data transfertypes.FungibleTokenPacketData
+	if err := json.Unmarshal(packet.GetData(), &data); err != nil {
+		return fmt.Errorf("failed to decode transfer packet data: %w", err)
+	}
+	amount, ok := math.NewIntFromString(data.Amount)
+	if !ok {
+		return fmt.Errorf("invalid transfer packet amount %q", data.Amount)
+	}
+	denom := receivedDenom(packet, data.Denom)
+
+	before, err := node.Chain.GetBalance(ctx, data.Receiver, denom)
+	if err != nil {
+		return err
+	}
+	if _, err := node.relayMsgs(ctx, destKey, updateMsg, recvMsg); err != nil {
+		return fmt.Errorf("failed to relay packet %d: %w", sequence, err)
+	}
+	received, err := node.Chain.GetBalance(ctx, data.Receiver, denom)
+	if err != nil {
+		return err
+	}
+	if !received.Sub(before).Equal(amount) {
+		return fmt.Errorf("expected %s to receive %s%s from packet %d, balance went from %s to %s",
+			data.Receiver, amount, denom, sequence, before, received)
+	}
+
+	// The client is already updated to the proof height, so the message can be submitted as is.
+	txResp, err := node.relayMsgs(ctx, destKey, nil, recvMsg)
+	redundant := chantypes.ErrRedundantTx.Error()
+	switch {
+	case err != nil && !strings.Contains(err.Error(), redundant):
+		return fmt.Errorf("resubmitting packet %d failed for another reason than being redundant: %w", sequence, err)
+	case err == nil && txResp.Code != 0 && !strings.Contains(txResp.RawLog, redundant):
+		return fmt.Errorf("resubmitting packet %d failed for another reason than being redundant: %s", sequence, txResp.RawLog)
+	}
+
+	after, err := node.Chain.GetBalance(ctx, data.Receiver, denom)
+	if err != nil {
+		return err
+	}
+	if !after.Equal(received) {
+		return fmt.Errorf("packet %d was received twice, balance of %s went from %s to %s%s",
+			sequence, data.Receiver, received, after, denom)
+	}
+	return nil
+}
+
+// recvPacketMsgs builds a MsgRecvPacket for the packet sent by src on srcPortID/srcChannelID with the given sequence,
+// and the MsgUpdateClient this chain's light client of src needs to verify it, if any.
+func (node *Node) recvPacketMsgs(ctx context.Context, src *Node, srcPortID, srcChannelID string, sequence uint64, destKey string) (types.Msg, *chantypes.MsgRecvPacket, error) {
+	packet, err := src.findSentPacket(ctx, srcPortID, srcChannelID, sequence)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, err := node.AccountKeyBech32(ctx, destKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value, proof, proofHeight, updateMsg, err := node.proveCounterpartyKey(ctx, src, packet.DestinationPort, packet.DestinationChannel,
+		host.PacketCommitmentKey(srcPortID, srcChannelID, sequence), signer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to prove packet commitment: %w", err)
+	}
+	if len(value) == 0 {
+		return nil, nil, fmt.Errorf("no commitment for packet %d on %s/%s, it may have been acknowledged already", sequence, srcPortID, srcChannelID)
+	}
+	return updateMsg, chantypes.NewMsgRecvPacket(packet, proof, proofHeight, signer), nil
+}
+
+// proveCounterpartyKey queries key from the ibc store of counterparty with a proof this chain's light client of
+// counterparty, underlying portID/channelID, can verify. It returns the value, its proof and proof height, and the
+// MsgUpdateClient, signed by signer, the light client needs to verify the proof, if any.
+func (node *Node) proveCounterpartyKey(ctx context.Context, counterparty *Node, portID, channelID string, key []byte, signer string) ([]byte, []byte, clienttypes.Height, types.Msg, error) {
+	clientID, trustedHeight, err := node.channelClient(ctx, portID, channelID)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, nil, err
+	}
+
+	// A value queried at height h is proven against the app hash committed in the header of height h+1.
+	latest, err := counterparty.Height(ctx)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, nil, err
+	}
+	queryHeight := int64(latest) - 1
+	updateClient := true
+	if trustedHeight.RevisionHeight >= latest {
+		queryHeight = int64(trustedHeight.RevisionHeight) - 1
+		updateClient = false
+	}
+
+	value, proof, err := counterparty.QueryWithProof(ctx, "ibc", key, queryHeight)
+	if err != nil {
+		return nil, nil, clienttypes.Height{}, nil, err
+	}
+	proofHeight := clienttypes.NewHeight(trustedHeight.RevisionNumber, uint64(queryHeight)+1)
+
+	var updateMsg types.Msg
+	if updateClient {
+		header, err := counterparty.lightClientHeader(ctx, trustedHeight, int64(proofHeight.RevisionHeight))
+		if err != nil {
+			return nil, nil, clienttypes.Height{}, nil, err
+		}
+		updateMsg, err = clienttypes.NewMsgUpdateClient(clientID, header, signer)
+		if err != nil {
+			return nil, nil, clienttypes.Height{}, nil, err
+		}
+	}
+	return value, proof, proofHeight, updateMsg, nil
+}
+
+// relayMsgs broadcasts the optional client update and the packet message in a single tx signed by keyName
+// and returns the committed tx response.
+func (node *Node) relayMsgs(ctx context.Context, keyName string, updateMsg types.Msg, packetMsg types.Msg) (*types.TxResponse, error) {
+	var msgs []types.Msg
+	if updateMsg != nil {
+		msgs = append(msgs, updateMsg)
+	}
+	msgs = append(msgs, packetMsg)
+
+	txHash, err := node.broadcastMsgs(ctx, keyName, manualRelayGasLimit, msgs...)
+	if err != nil {
+		return nil, err
+	}
+	txResp, err := node.GetTransaction(node.CliContext(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHash, err)
+	}
+	return txResp, nil
+}
+
+// receivedDenom returns the denom the receiving chain credits for a transfer packet of baseDenom.
+func receivedDenom(packet chantypes.Packet, baseDenom string) string {
+	if transfertypes.ReceiverChainIsSource(packet.GetSourcePort(), packet.GetSourceChannel(), baseDenom) {
+		unprefixed := baseDenom[len(transfertypes.GetDenomPrefix(packet.GetSourcePort(), packet.GetSourceChannel())):]
+		return transfertypes.ParseDenomTrace(unprefixed).IBCDenom()
+	}
+	prefixed := transfertypes.GetPrefixedDenom(packet.GetDestPort(), packet.GetDestChannel(), baseDenom)
+	return transfertypes.ParseDenomTrace(prefixed).IBCDenom()
+}
+
+// findSentPacket looks up the packet with the given sequence from the send_packet event of the tx which sent it.
+func (node *Node) findSentPacket(ctx context.Context, portID, channelID string, sequence uint64) (chantypes.Packet, error) {
+	query := fmt.Sprintf("send_packet.packet_src_port='%s' AND send_packet.packet_src_channel='%s' AND send_packet.packet_sequence='%d'",
+		portID, channelID, sequence)
+	page, perPage := 1, 1
+	res, err := node.Client.TxSearch(ctx, query, false, &page, &perPage, "")
+	if err != nil {
+		return chantypes.Packet{}, fmt.Errorf("failed to search send packet tx: %w", err)
+	}
+	if len(res.Txs) == 0 {
+		return chantypes.Packet{}, fmt.Errorf("no tx sent packet %d on %s/%s", sequence, portID, channelID)
+	}
+
+	for _, event := range res.Txs[0].TxResult.Events {
+		if event.Type != chantypes.EventTypeSendPacket {
+			continue
+		}
+		attrs := make(map[string]string, len(event.Attributes))
+		for _, attr := range event.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+		if attrs[chantypes.AttributeKeySequence] != strconv.FormatUint(sequence, 10) ||
+			attrs[chantypes.AttributeKeySrcChannel] != channelID {
+			continue
+		}
+
+		data, err := hex.DecodeString(attrs[chantypes.AttributeKeyDataHex])
+		if err != nil {
+			return chantypes.Packet{}, fmt.Errorf("failed to decode packet data: %w", err)
+		}
+		timeoutHeight, err := clienttypes.ParseHeight(attrs[chantypes.AttributeKeyTimeoutHeight])
+		if err != nil {
+			return chantypes.Packet{}, fmt.Errorf("failed to parse packet timeout height: %w", err)
+		}
+		timeoutTimestamp, err := strconv.ParseUint(attrs[chantypes.AttributeKeyTimeoutTimestamp], 10, 64)
+		if err != nil {
+			return chantypes.Packet{}, fmt.Errorf("failed to parse packet timeout timestamp: %w", err)
+		}
+		return chantypes.NewPacket(data, sequence, portID, channelID,
+			attrs[chantypes.AttributeKeyDstPort], attrs[chantypes.AttributeKeyDstChannel], timeoutHeight, timeoutTimestamp), nil
+	}
+	return chantypes.Packet{}, fmt.Errorf("send_packet event for packet %d on %s/%s not found", sequence, portID, channelID)
+}
+
+// channelClient returns the id and latest height of the light client underlying the given channel.
+func (node *Node) channelClient(ctx context.Context, portID, channelID string) (string, clienttypes.Height, error) {
+	stdout, _, err := node.ExecQuery(ctx, "ibc", "channel", "client-state", portID, channelID)
+	if err != nil {
+		return "", clienttypes.Height{}, err
+	}
+
+	var res struct {
+		IdentifiedClientState struct {
+			ClientID    string `json:"client_id"`
+			ClientState struct {
+				LatestHeight struct {
+					RevisionNumber string `json:"revision_number"`
+					RevisionHeight string `json:"revision_height"`
+				} `json:"latest_height"`
+			} `json:"client_state"`
+		} `json:"identified_client_state"`
+	}
+	if err := json.Unmarshal(stdout, &res); err != nil {
+		return "", clienttypes.Height{}, err
+	}
+
+	latest := res.IdentifiedClientState.ClientState.LatestHeight
+	height, err := clienttypes.ParseHeight(latest.RevisionNumber + "-" + latest.RevisionHeight)
+	if err != nil {
+		return "", clienttypes.Height{}, fmt.Errorf("failed to parse client latest height: %w", err)
+	}
+	return res.IdentifiedClientState.ClientID, height, nil
+}
+
+// lightClientHeader builds a tendermint light client header of this chain at height, trusting trustedHeight.
+func (node *Node) lightClientHeader(ctx context.Context, trustedHeight clienttypes.Height, height int64) (*ibctm.Header, error) {
+	commit, err := node.Client.Commit(ctx, &height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit at height %d: %w", height, err)
+	}
+	valSet, err := node.validatorSet(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	// The trusted consensus state commits to the validators of the block following it.
+	trustedValSet, err := node.validatorSet(ctx, int64(trustedHeight.RevisionHeight)+1)
+	if err != nil {
+		return nil, err
+	}
+
+	valSetProto, err := valSet.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	trustedValSetProto, err := trustedValSet.ToProto()
+	if err != nil {
+		return nil, err
+	}
+	return &ibctm.Header{
+		SignedHeader:      commit.SignedHeader.ToProto(),
+		ValidatorSet:      valSetProto,
+		TrustedHeight:     trustedHeight,
+		TrustedValidators: trustedValSetProto,
+	}, nil
+}
+
+// validatorSet returns the complete validator set of this chain at height.
+func (node *Node) validatorSet(ctx context.Context, height int64) (*cmttypes.ValidatorSet, error) {
+	var vals []*cmttypes.Validator
+	perPage := 100
+	for page := 1; ; page++ {
+		res, err := node.Client.Validators(ctx, &height, &page, &perPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get validators at height %d: %w", height, err)
+		}
+		vals = append(vals, res.Validators...)
+		if len(vals) >= res.Total {
+			break
+		}
+	}
+	return cmttypes.NewValidatorSet(vals), nil
+}
+
+// broadcastMsgs signs msgs with keyName and broadcasts them in a single tx, paying fees at the chain gas prices.
+func (node *Node) broadcastMsgs(ctx context.Context, keyName string, gasLimit uint64, msgs ...types.Msg) (string, error) {
+	gasPrices, err := types.ParseDecCoins(node.Chain.Config().GasPrices)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse gas prices: %w", err)
+	}
+	fees := make(types.Coins, 0, len(gasPrices))
+	for _, gp := range gasPrices {
+		fees = append(fees, types.NewCoin(gp.Denom, gp.Amount.MulInt(math.NewIntFromUint64(gasLimit)).Ceil().RoundInt()))
+	}
+
+	txConfig := node.Chain.Config().EncodingConfig.TxConfig
+	txBuilder := txConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msgs...); err != nil {
+		return "", err
+	}
+	txBuilder.SetGasLimit(gasLimit)
+	txBuilder.SetFeeAmount(fees.Sort())
+	bz, err := txConfig.TxJSONEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tx: %w", err)
+	}
+
+	unsigned, signed := tempJSONFileName("unsigned-tx"), tempJSONFileName("signed-tx")
+	defer node.removeFiles(ctx, unsigned, signed)
+	if err := node.WriteFile(ctx, bz, unsigned); err != nil {
+		return "", fmt.Errorf("writing unsigned tx to docker volume: %w", err)
+	}
+
+	signCmd := node.TxCommand(keyName, "sign", path.Join(node.HomeDir(), unsigned), "--output-document", path.Join(node.HomeDir(), signed))
+	if _, _, err := node.Exec(ctx, signCmd, nil); err != nil {
+		return "", fmt.Errorf("failed to sign tx: %w", err)
+	}
+
+	return node.ExecTx(ctx, keyName, "broadcast", path.Join(node.HomeDir(), signed))
+}
+
+// FlushPackets relays every packet sent by src on srcPortID/srcChannelID which dst has not received yet, then relays
+// their acknowledgements back to src, without a relayer. Both chains pay and sign with the key dstKey, which must
+// exist and be funded on both, e.g. as set up by SetupRelayerKey. It returns the number of packets received by dst.
+func FlushPackets(ctx context.Context, src, dst *Node, srcPortID, srcChannelID, dstKey string) (relayed int, err error) {
+	dstPortID, dstChannelID, err := src.channelCounterparty(ctx, srcPortID, srcChannelID)
+	if err != nil {
+		return 0, err
+	}
+	sequences, err := src.packetCommitments(ctx, srcPortID, srcChannelID)
+	if err != nil {
+		return 0, err
+	}
+	unreceived, err := dst.unreceivedPackets(ctx, dstPortID, dstChannelID, sequences)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, sequence := range unreceived {
+		updateMsg, recvMsg, err := dst.recvPacketMsgs(ctx, src, srcPortID, srcChannelID, sequence, dstKey)
+		if err != nil {
+			return relayed, err
+		}
+		txResp, err := dst.relayMsgs(ctx, dstKey, updateMsg, recvMsg)
+		if err != nil {
+			return relayed, fmt.Errorf("failed to relay packet %d: %w", sequence, err)
+		}
+		if txResp.Code != 0 {
+			return relayed, fmt.Errorf("relaying packet %d failed with code %d: %s", sequence, txResp.Code, txResp.RawLog)
+		}
+		relayed++
+
+		ackHex, ok := AttributeValue(txResp.Events, chantypes.EventTypeWriteAck, chantypes.AttributeKeyAckHex)
+		if !ok {
+			// Async acknowledgements are written later by the receiving application.
+			continue
+		}
+		ack, err := hex.DecodeString(ackHex)
+		if err != nil {
+			return relayed, fmt.Errorf("failed to decode acknowledgement of packet %d: %w", sequence, err)
+		}
+		if err := src.acknowledgePacket(ctx, dst, recvMsg.Packet, ack, dstKey); err != nil {
+			return relayed, err
+		}
+	}
+	return relayed, nil
+}
+
+// acknowledgePacket relays the acknowledgement written by dst for packet, sent by this node, back to this node.
+func (node *Node) acknowledgePacket(ctx context.Context, dst *Node, packet chantypes.Packet, ack []byte, keyName string) error {
+	signer, err := node.AccountKeyBech32(ctx, keyName)
+	if err != nil {
+		return err
+	}
+
+	value, proof, proofHeight, updateMsg, err := node.proveCounterpartyKey(ctx, dst, packet.SourcePort, packet.SourceChannel,
+		host.PacketAcknowledgementKey(packet.DestinationPort, packet.DestinationChannel, packet.Sequence), signer)
+	if err != nil {
+		return fmt.Errorf("failed to prove acknowledgement of packet %d: %w", packet.Sequence, err)
+	}
+	if len(value) == 0 {
+		return fmt.Errorf("no acknowledgement of packet %d on %s/%s", packet.Sequence, packet.DestinationPort, packet.DestinationChannel)
+	}
+
+	txResp, err := node.relayMsgs(ctx, keyName, updateMsg, chantypes.NewMsgAcknowledgement(packet, ack, proof, proofHeight, signer))
+	if err != nil {
+		return fmt.Errorf("failed to relay acknowledgement of packet %d: %w", packet.Sequence, err)
+	}
+	if txResp.Code != 0 {
+		return fmt.Errorf("relaying acknowledgement of packet %d failed with code %d: %s", packet.Sequence, txResp.Code, txResp.RawLog)
+	}
+	return nil
+}
+
+// channelCounterparty returns the port and channel ids of the counterparty end of the given channel.
+func (node *Node) channelCounterparty(ctx context.Context, portID, channelID string) (string, string, error) {
+	var res struct {
+		Channel struct {
+			Counterparty struct {
+				PortID    string `json:"port_id"`
+				ChannelID string `json:"channel_id"`
+			} `json:"counterparty"`
+		} `json:"channel"`
+	}
+	if err := node.QueryJSON(ctx, &res, "ibc", "channel", "end", portID, channelID); err != nil {
+		return "", "", err
+	}
+	return res.Channel.Counterparty.PortID, res.Channel.Counterparty.ChannelID, nil
+}
+
+// packetCommitments returns the sequences of the packets sent on the given channel which were not acknowledged
+// or timed out yet.
+func (node *Node) packetCommitments(ctx context.Context, portID, channelID string) ([]uint64, error) {
+	var res struct {
+		Commitments []struct {
+			Sequence string `json:"sequence"`
+		} `json:"commitments"`
+	}
+	if err := node.QueryJSON(ctx, &res, "ibc", "channel", "packet-commitments", portID, channelID, "--limit", "10000"); err != nil {
+		return nil, err
+	}
+
+	sequences := make([]uint64, len(res.Commitments))
+	for i, commitment := range res.Commitments {
+		sequence, err := strconv.ParseUint(commitment.Sequence, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse packet sequence: %w", err)
+		}
+		sequences[i] = sequence
+	}
+	return sequences, nil
+}
+
+// unreceivedPackets returns the sequences among the given ones which the given channel did not receive yet.
+func (node *Node) unreceivedPackets(ctx context.Context, portID, channelID string, sequences []uint64) ([]uint64, error) {
+	if len(sequences) == 0 {
+		return nil, nil
+	}
+	seqs := make([]string, len(sequences))
+	for i, sequence := range sequences {
+		seqs[i] = strconv.FormatUint(sequence, 10)
+	}
+
+	var res struct {
+		Sequences []string `json:"sequences"`
+	}
+	if err := node.QueryJSON(ctx, &res, "ibc", "channel", "unreceived-packets", portID, channelID, "--sequences", strings.Join(seqs, ",")); err != nil {
+		return nil, err
+	}
+
+	unreceived := make([]uint64, len(res.Sequences))
+	for i, seq := range res.Sequences {
+		sequence, err := strconv.ParseUint(seq, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse packet sequence: %w", err)
+		}
+		unreceived[i] = sequence
+	}
+	return unreceived, nil
+}
@@ -0,0 +1,93 @@
+package cosmos
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RecordedTx is a single transaction recorded by a TxRecorder.
+type RecordedTx struct {
+	KeyName string   `json:"key_name"`
+	Command []string `json:"command"`
+}
+
+// TxRecorder records every transaction broadcast by a Node to a file, one JSON encoded RecordedTx per line,
+// so the sequence can be replayed with ReplayTxs. This includes the txs of helpers such as BankSend or
+// StoreContract; files such helpers write to the node home dir are not recorded.
+type TxRecorder struct {
+	*Node
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewTxRecorder attaches a TxRecorder to node writing to recordPath, replacing any recorder attached before.
+// Any existing file is truncated. The recorder stays attached until closed.
+func NewTxRecorder(node *Node, recordPath string) (*TxRecorder, error) {
+	f, err := os.Create(recordPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tx record file: %w", err)
+	}
+	r := &TxRecorder{
+		Node: node,
+		file: f,
+		enc:  json.NewEncoder(f),
+	}
+	node.recorder.Store(r)
+	return r, nil
+}
+
+// record records the transaction before it is broadcast.
+// Transactions are recorded even if they fail, since failures are part of the sequence to reproduce.
+func (r *TxRecorder) record(keyName string, command []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(RecordedTx{KeyName: keyName, Command: command}); err != nil {
+		return fmt.Errorf("failed to record tx: %w", err)
+	}
+	return nil
+}
+
+// Close detaches the recorder from the node, then flushes and closes the record file.
+func (r *TxRecorder) Close() error {
+	r.Node.recorder.CompareAndSwap(r, nil)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Sync(); err != nil {
+		_ = r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
+
+// ReplayTxs executes the transactions recorded by a TxRecorder at recordPath, in order, against node.
+// The keys used in the recording must exist in the node's keyring.
+func (node *Node) ReplayTxs(ctx context.Context, recordPath string) error {
+	f, err := os.Open(recordPath)
+	if err != nil {
+		return fmt.Errorf("failed to open tx record file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for i := 1; scanner.Scan(); i++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var tx RecordedTx
+		if err := json.Unmarshal(scanner.Bytes(), &tx); err != nil {
+			return fmt.Errorf("failed to decode recorded tx on line %d: %w", i, err)
+		}
+		if _, err := node.ExecTx(ctx, tx.KeyName, tx.Command...); err != nil {
+			return fmt.Errorf("failed to replay tx on line %d: %w", i, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -1,7 +1,16 @@
 package cosmos
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 const (
@@ -17,6 +26,15 @@ const (
 	ProposalStatusRejected      = "PROPOSAL_STATUS_REJECTED"
 	ProposalStatusVotingPeriod  = "PROPOSAL_STATUS_VOTING_PERIOD"
 	ProposalStatusDepositPeriod = "PROPOSAL_STATUS_DEPOSIT_PERIOD"
+
+	SequencerStatusBonded    = "OPERATING_STATUS_BONDED"
+	SequencerStatusUnbonding = "OPERATING_STATUS_UNBONDING"
+	SequencerStatusUnbonded  = "OPERATING_STATUS_UNBONDED"
+
+	RollappStatusRegistered = "registered"
+	RollappStatusActive     = "active"
+	RollappStatusFinalized  = "finalized"
+	RollappStatusFrozen     = "frozen"
 )
 
 // TxProposalv1 contains chain proposal transaction detail for gov module v1 (sdk v0.46.0+)
@@ -108,6 +126,13 @@ type ProposalDeposit struct {
 	Amount string `json:"amount"`
 }
 
+// Deposit is a deposit made on a governance proposal.
+type Deposit struct {
+	ProposalID string            `json:"proposal_id"`
+	Depositor  string            `json:"depositor"`
+	Amount     []ProposalDeposit `json:"amount"`
+}
+
 type ParamChange struct {
 	Subspace string `json:"subspace"`
 	Key      string `json:"key"`
@@ -119,6 +144,14 @@ type ContractStateModels struct {
 	Value string `json:"value"`
 }
 
+// ContractInfo is the metadata of a CosmWasm contract.
+type ContractInfo struct {
+	CodeID  string `json:"code_id"`
+	Creator string `json:"creator"`
+	Admin   string `json:"admin"`
+	Label   string `json:"label"`
+}
+
 type BuildDependency struct {
 	Parent  string `json:"parent"`
 	Version string `json:"version"`
@@ -167,3 +200,215 @@ type QueryModuleAccountResponse struct {
 		Name string `json:"name"`
 	} `json:"account"`
 }
+
+// SlashEvent is a slash event emitted by the x/slashing module at the end of a block.
+type SlashEvent struct {
+	Height uint64
+	// Consensus address of the slashed validator.
+	Address string
+	Power   int64
+	Reason  string
+	// Consensus address of the validator jailed as part of the slash, empty if it was not jailed.
+	Jailed string
+}
+
+// PaginationResponse is the pagination section of a paginated query response.
+type PaginationResponse struct {
+	// NextKey is base64 encoded in the JSON output.
+	NextKey string `json:"next_key"`
+	Total   string `json:"total"`
+}
+
+// pageKeyFlag returns the value of the --page-key query flag for the next page, empty on the last page.
+// The flag takes the raw key bytes, not the base64 encoding of the query output.
+func (p PaginationResponse) pageKeyFlag() (string, error) {
+	key, err := base64.StdEncoding.DecodeString(p.NextKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid next key %q: %w", p.NextKey, err)
+	}
+	// Command arguments can not carry NUL bytes.
+	if bytes.IndexByte(key, 0) >= 0 {
+		return "", fmt.Errorf("next key %q can not be passed as --page-key", p.NextKey)
+	}
+	return string(key), nil
+}
+
+// UnbondingDelegation is the staking unbonding delegation query response.
+type UnbondingDelegation struct {
+	DelegatorAddress string                     `json:"delegator_address"`
+	ValidatorAddress string                     `json:"validator_address"`
+	Entries          []UnbondingDelegationEntry `json:"entries"`
+}
+
+type UnbondingDelegationEntry struct {
+	CreationHeight string    `json:"creation_height"`
+	CompletionTime time.Time `json:"completion_time"`
+	InitialBalance string    `json:"initial_balance"`
+	Balance        string    `json:"balance"`
+}
+
+// Delegation is the staking delegation query response.
+type Delegation struct {
+	Delegation struct {
+		DelegatorAddress string `json:"delegator_address"`
+		ValidatorAddress string `json:"validator_address"`
+		Shares           string `json:"shares"`
+	} `json:"delegation"`
+	Balance sdk.Coin `json:"balance"`
+}
+
+// Snapshot is a state sync snapshot stored by a node.
+type Snapshot struct {
+	Height uint64
+	Format uint32
+	Chunks uint32
+}
+
+// StartupTimings holds the duration of the setup steps of a node, e.g. for tracking setup time in CI.
+type StartupTimings struct {
+	InitFullNodeFiles   time.Duration
+	CreateNodeContainer time.Duration
+	StartContainer      time.Duration
+}
+
+// Sequencer is a rollapp sequencer registered on the hub.
+type Sequencer struct {
+	SequencerAddress string `json:"sequencerAddress"`
+	RollappID        string `json:"rollappId"`
+	Status           string `json:"status"`
+	Proposer         bool   `json:"proposer"`
+	Jailed           bool   `json:"jailed"`
+}
+
+// RollappRegistration describes a rollapp to register on the hub with RegisterMultipleRollapps.
+type RollappRegistration struct {
+	ChainID       string
+	MaxSequencers string
+	// SequencerAddr is the hub address of the initial sequencer, which must be a key in the hub keyring dir.
+	SequencerAddr string
+	// SequencerPubKey is the dymint public key of the sequencer, as printed by ShowSeq on the rollapp.
+	SequencerPubKey string
+}
+
+// RollappSummary is the state of a rollapp registered on the hub, as returned by QueryAllRollapps.
+type RollappSummary struct {
+	ChainID string
+	// Frozen is set once the hub froze the rollapp, e.g. after a successful fraud proof.
+	Frozen bool
+	// LatestStateIndex and LatestFinalizedStateIndex are empty until the rollapp submits resp. finalizes a state update.
+	LatestStateIndex          string
+	LatestFinalizedStateIndex string
+	// Sequencers is the number of sequencers registered for the rollapp, in any status.
+	Sequencers int
+	// Status is derived from the fields above, one of the RollappStatus constants.
+	Status string
+}
+
+// rollappStatus returns RollappStatusFrozen for a frozen rollapp, RollappStatusRegistered until it submits a state
+// update, RollappStatusFinalized once its latest state update is finalized and RollappStatusActive otherwise.
+func (r RollappSummary) rollappStatus() string {
+	switch {
+	case r.Frozen:
+		return RollappStatusFrozen
+	case r.LatestStateIndex == "":
+		return RollappStatusRegistered
+	case r.LatestFinalizedStateIndex == r.LatestStateIndex:
+		return RollappStatusFinalized
+	default:
+		return RollappStatusActive
+	}
+}
+
+// BatchInfo describes a batch of rollapp blocks submitted to the settlement layer.
+type BatchInfo struct {
+	StartHeight uint64
+	EndHeight   uint64
+	// Size is the total size in bytes of the blocks in the batch.
+	Size int
+}
+
+// StateInfo is a rollapp state update submitted to the hub by the rollapp's sequencer.
+type StateInfo struct {
+	StateInfoIndex struct {
+		RollappID string `json:"rollappId"`
+		Index     string `json:"index"`
+	} `json:"stateInfoIndex"`
+	Sequencer      string `json:"sequencer"`
+	StartHeight    string `json:"startHeight"`
+	NumBlocks      string `json:"numBlocks"`
+	DAPath         string `json:"DAPath"`
+	CreationHeight string `json:"creationHeight"`
+	Status         string `json:"status"`
+}
+
+// ContainerMemStats is the memory usage of a node container, in bytes.
+type ContainerMemStats struct {
+	Usage    uint64
+	MaxUsage uint64
+	Limit    uint64
+	// Failcnt is the number of times the memory limit was hit.
+	Failcnt uint64
+}
+
+// ActivitySummary aggregates the transactions of a range of blocks.
+type ActivitySummary struct {
+	StartHeight uint64
+	EndHeight   uint64
+	TotalTxs    int
+	// MsgCounts maps message type urls to the number of such messages.
+	MsgCounts    map[string]int
+	TotalGasUsed int64
+	TotalFees    sdk.Coins
+}
+
+// String returns a human readable report of the summary.
+func (s ActivitySummary) String() string {
+	msgTypes := make([]string, 0, len(s.MsgCounts))
+	for msgType := range s.MsgCounts {
+		msgTypes = append(msgTypes, msgType)
+	}
+	sort.Strings(msgTypes)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "blocks %d-%d: %d txs, %d gas used, fees %s\n", s.StartHeight, s.EndHeight, s.TotalTxs, s.TotalGasUsed, s.TotalFees)
+	for _, msgType := range msgTypes {
+		fmt.Fprintf(&b, "  %s: %d\n", msgType, s.MsgCounts[msgType])
+	}
+	return b.String()
+}
+
+// TxOptions controls how ExecTxWithOptions broadcasts a tx and waits for it.
+type TxOptions struct {
+	// WaitForBlocks is the number of blocks to wait for after the tx is accepted, 0 meaning don't wait at all.
+	WaitForBlocks int
+	// BroadcastMode is passed as --broadcast-mode when set.
+	BroadcastMode string
+}
+
+// DefaultTxOptions returns the options ExecTx uses: wait for 2 blocks with the CLI default broadcast mode.
+func DefaultTxOptions() TxOptions {
+	return TxOptions{WaitForBlocks: 2}
+}
+
+// TallyResult is the vote tally of a governance proposal.
+type TallyResult struct {
+	Yes        math.Int
+	No         math.Int
+	Abstain    math.Int
+	NoWithVeto math.Int
+}
+
+// Total returns the voting power of all votes cast.
+func (t TallyResult) Total() math.Int {
+	return t.Yes.Add(t.No).Add(t.Abstain).Add(t.NoWithVeto)
+}
+
+// EpochInfo is an epoch tracked by the x/epochs module.
+type EpochInfo struct {
+	Identifier   string `json:"identifier"`
+	Duration     string `json:"duration"`
+	CurrentEpoch string `json:"current_epoch"`
+
+	CurrentEpochStartHeight string    `json:"current_epoch_start_height"`
+	CurrentEpochStartTime   time.Time `json:"current_epoch_start_time"`
+}
@@ -0,0 +1,31 @@
+package cosmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageKeyFlag(t *testing.T) {
+	t.Parallel()
+
+	// "rollapp_1-1/" as printed in the next_key of a query output.
+	key, err := PaginationResponse{NextKey: "cm9sbGFwcF8xLTEv"}.pageKeyFlag()
+	require.NoError(t, err)
+	require.Equal(t, "rollapp_1-1/", key)
+
+	_, err = PaginationResponse{NextKey: "not base64!"}.pageKeyFlag()
+	require.ErrorContains(t, err, "invalid next key")
+
+	_, err = PaginationResponse{NextKey: "AAE="}.pageKeyFlag()
+	require.ErrorContains(t, err, "can not be passed as --page-key")
+}
+
+func TestRollappStatus(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, RollappStatusRegistered, RollappSummary{}.rollappStatus())
+	require.Equal(t, RollappStatusActive, RollappSummary{LatestStateIndex: "2", LatestFinalizedStateIndex: "1"}.rollappStatus())
+	require.Equal(t, RollappStatusFinalized, RollappSummary{LatestStateIndex: "2", LatestFinalizedStateIndex: "2"}.rollappStatus())
+	require.Equal(t, RollappStatusFrozen, RollappSummary{Frozen: true, LatestStateIndex: "2"}.rollappStatus())
+}
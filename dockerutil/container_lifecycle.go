@@ -1,8 +1,12 @@
 package dockerutil
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"strings"
 
@@ -11,6 +15,7 @@ import (
 	"github.com/docker/docker/api/types/network"
 	dockerclient "github.com/docker/docker/client"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"go.uber.org/zap"
 
@@ -43,6 +48,7 @@ func (c *ContainerLifecycle) CreateContainer(
 	hostName string,
 	cmd []string,
 	env []string,
+	resources *container.Resources,
 ) error {
 	imageRef := image.Ref()
 	c.log.Info(
@@ -59,6 +65,17 @@ func (c *ContainerLifecycle) CreateContainer(
 
 	c.preStartListeners = listeners
 
+	hostConfig := &container.HostConfig{
+		Binds:           volumeBinds,
+		PortBindings:    pb,
+		PublishAllPorts: true,
+		AutoRemove:      false,
+		DNS:             []string{},
+	}
+	if resources != nil {
+		hostConfig.Resources = *resources
+	}
+
 	cc, err := c.client.ContainerCreate(
 		ctx,
 		&container.Config{
@@ -74,13 +91,7 @@ func (c *ContainerLifecycle) CreateContainer(
 
 			ExposedPorts: ports,
 		},
-		&container.HostConfig{
-			Binds:           volumeBinds,
-			PortBindings:    pb,
-			PublishAllPorts: true,
-			AutoRemove:      false,
-			DNS:             []string{},
-		},
+		hostConfig,
 		&network.NetworkingConfig{
 			EndpointsConfig: map[string]*network.EndpointSettings{
 				networkID: {},
@@ -159,6 +170,147 @@ func (c *ContainerLifecycle) GetHostPorts(ctx context.Context, portIDs ...string
 	return ports, nil
 }
 
+// WaitForExit blocks until the container is no longer running and returns its exit code.
+// It returns early with the context error if ctx is done before the container exits.
+func (c *ContainerLifecycle) WaitForExit(ctx context.Context) (int, error) {
+	waitCh, errCh := c.client.ContainerWait(ctx, c.id, container.WaitConditionNotRunning)
+	select {
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	case err := <-errCh:
+		return -1, err
+	case res := <-waitCh:
+		if res.Error != nil {
+			return int(res.StatusCode), errors.New(res.Error.Message)
+		}
+		return int(res.StatusCode), nil
+	}
+}
+
+// ExitState returns the exit code of the stopped container as recorded by docker inspect,
+// along with the reason docker gives for the exit, if any, e.g. when the container was OOM killed.
+func (c *ContainerLifecycle) ExitState(ctx context.Context) (int, string, error) {
+	cjson, err := c.client.ContainerInspect(ctx, c.id)
+	if err != nil {
+		return -1, "", err
+	}
+	if cjson.State.Running {
+		return -1, "", fmt.Errorf("container %s is still running", c.containerName)
+	}
+
+	reason := cjson.State.Error
+	if cjson.State.OOMKilled {
+		reason = "OOM killed"
+	}
+	return cjson.State.ExitCode, reason, nil
+}
+
+// Logs returns the combined stdout and stderr output of the container.
+func (c *ContainerLifecycle) Logs(ctx context.Context) ([]byte, error) {
+	rc, err := c.client.ContainerLogs(ctx, c.id, dockertypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	// Logs are multiplexed into one stream; see docs for ContainerLogs.
+	buf := new(bytes.Buffer)
+	if _, err := stdcopy.StdCopy(buf, buf, rc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StreamLogs follows the combined stdout and stderr output of the container, starting with its full history.
+// The stream ends when the container stops or ctx is cancelled; closing the returned reader releases the
+// underlying connection.
+func (c *ContainerLifecycle) StreamLogs(ctx context.Context) (io.ReadCloser, error) {
+	rc, err := c.client.ContainerLogs(ctx, c.id, dockertypes.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Logs are multiplexed into one stream; see docs for ContainerLogs.
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, rc)
+		if ctx.Err() != nil {
+			err = nil
+		}
+		_ = pw.CloseWithError(err)
+		_ = rc.Close()
+	}()
+	return &logStream{PipeReader: pr, conn: rc}, nil
+}
+
+// logStream is the reader of a followed log stream which also closes the docker connection when closed.
+type logStream struct {
+	*io.PipeReader
+	conn io.Closer
+}
+
+func (s *logStream) Close() error {
+	_ = s.conn.Close()
+	return s.PipeReader.Close()
+}
+
+// Exec runs cmd inside the running container, like docker exec, and returns its stdout and stderr.
+// An error is returned if the command exits with a non-zero code.
+func (c *ContainerLifecycle) Exec(ctx context.Context, cmd []string, env []string) ([]byte, []byte, error) {
+	exec, err := c.client.ContainerExecCreate(ctx, c.id, dockertypes.ExecConfig{
+		Cmd:          cmd,
+		Env:          env,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("create exec in container %s: %w", c.containerName, err)
+	}
+
+	resp, err := c.client.ContainerExecAttach(ctx, exec.ID, dockertypes.ExecStartCheck{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("attach to exec in container %s: %w", c.containerName, err)
+	}
+	defer resp.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader); err != nil {
+		return nil, nil, err
+	}
+
+	inspect, err := c.client.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return stdout.Bytes(), stderr.Bytes(), err
+	}
+	if inspect.ExitCode != 0 {
+		return stdout.Bytes(), stderr.Bytes(), fmt.Errorf("exec in container %s exited with code %d: %s",
+			c.containerName, inspect.ExitCode, stderr.String())
+	}
+	return stdout.Bytes(), stderr.Bytes(), nil
+}
+
+// Stats returns a single snapshot of the container's resource usage.
+func (c *ContainerLifecycle) Stats(ctx context.Context) (*dockertypes.StatsJSON, error) {
+	res, err := c.client.ContainerStatsOneShot(ctx, c.id)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var stats dockertypes.StatsJSON
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("decode stats of container %s: %w", c.containerName, err)
+	}
+	return &stats, nil
+}
+
 // Running will inspect the container and check its state to determine if it is currently running.
 // If the container is running nil will be returned, otherwise an error is returned.
 func (c *ContainerLifecycle) Running(ctx context.Context) error {
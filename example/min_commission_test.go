@@ -0,0 +1,71 @@
+package example
+
+import (
+	"context"
+	"testing"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	test "github.com/decentrio/rollup-e2e-testing"
+	"github.com/decentrio/rollup-e2e-testing/cosmos"
+	"github.com/decentrio/rollup-e2e-testing/testreporter"
+	"github.com/decentrio/rollup-e2e-testing/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestMinCommissionRate asserts that creating a validator below the staking min commission rate is rejected.
+func TestMinCommissionRate(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+
+	hubConfig := dymensionConfig.Clone()
+	hubConfig.CommissionRate = "0.10"
+	hubConfig.ModifyGenesis = cosmos.ModifyGenesis([]cosmos.GenesisKV{
+		cosmos.NewGenesisKV("app_state.staking.params.min_commission_rate", "0.050000000000000000"),
+	})
+
+	numHubVals := 1
+	numHubFullNodes := 1
+	cf := cosmos.NewBuiltinChainFactory(zaptest.NewLogger(t), []*cosmos.ChainSpec{
+		{
+			Name:          "dymension-hub",
+			ChainConfig:   hubConfig,
+			NumValidators: &numHubVals,
+			NumFullNodes:  &numHubFullNodes,
+		},
+	})
+
+	chains, err := cf.Chains(t.Name())
+	require.NoError(t, err)
+	dymension := chains[0].(*cosmos.CosmosChain)
+
+	client, network := test.DockerSetup(t)
+	ic := test.NewSetup().AddChain(dymension)
+
+	rep := testreporter.NewNopReporter()
+	eRep := rep.RelayerExecReporter(t)
+
+	err = ic.Build(ctx, eRep, test.InterchainBuildOptions{
+		TestName:         t.Name(),
+		Client:           client,
+		NetworkID:        network,
+		SkipPathCreation: true,
+	})
+	require.NoError(t, err)
+
+	users := test.GetAndFundTestUsers(t, ctx, t.Name(), math.NewInt(1_000_000_000_000), dymension)
+	require.NoError(t, testutil.WaitForBlocks(ctx, 2, dymension))
+	user := users[0]
+
+	selfDelegation := sdk.NewCoin(dymension.Config().Denom, math.NewInt(1_000_000))
+
+	_, err = dymension.CreateValidator(ctx, user.KeyName(), selfDelegation, "0.01")
+	require.ErrorContains(t, err, "commission")
+
+	_, err = dymension.CreateValidator(ctx, user.KeyName(), selfDelegation, "0.10")
+	require.NoError(t, err)
+}
@@ -97,5 +97,6 @@ type Chain interface {
 // TransferOptions defines the options for an IBC packet transfer.
 type TransferOptions struct {
 	Timeout *IBCTimeout
-	Memo    string
+	// Memo is the memo of the transfer, e.g. a forward memo built with BuildPFMMemo.
+	Memo string
 }
@@ -0,0 +1,89 @@
+package ibc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PFMHop is a hop of a packet-forward-middleware route, forwarding the received tokens over Port/Channel
+// to Receiver on the next chain, then along Next if set.
+type PFMHop struct {
+	Receiver string
+	Port     string
+	Channel  string
+	// Timeout is the timeout of the forwarded packet, e.g. "10m". Empty means the middleware default.
+	Timeout string
+	Retries uint8
+	Next    *PFMHop
+}
+
+type pfmMemo struct {
+	Forward pfmForward `json:"forward"`
+}
+
+type pfmForward struct {
+	Receiver string   `json:"receiver"`
+	Port     string   `json:"port"`
+	Channel  string   `json:"channel"`
+	Timeout  string   `json:"timeout,omitempty"`
+	Retries  uint8    `json:"retries"`
+	Next     *pfmMemo `json:"next,omitempty"`
+}
+
+// BuildPFMMemo returns the packet-forward-middleware memo of a transfer forwarding the tokens over port/channel to
+// receiver on the next chain, then along next if set, to be used as TransferOptions.Memo.
+// timeout is the timeout of the forwarded packet, e.g. "10m", or empty for the middleware default.
+func BuildPFMMemo(receiver, port, channel string, next *PFMHop, timeout string, retries uint8) (string, error) {
+	memo, err := PFMHop{
+		Receiver: receiver,
+		Port:     port,
+		Channel:  channel,
+		Timeout:  timeout,
+		Retries:  retries,
+		Next:     next,
+	}.memo(1)
+	if err != nil {
+		return "", err
+	}
+
+	bz, err := json.Marshal(memo)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal forward memo: %w", err)
+	}
+	return string(bz), nil
+}
+
+// memo validates the hop, the index-th of the route, and returns its memo.
+func (hop PFMHop) memo(index int) (*pfmMemo, error) {
+	switch {
+	case hop.Receiver == "":
+		return nil, fmt.Errorf("hop %d: empty receiver", index)
+	case hop.Port == "":
+		return nil, fmt.Errorf("hop %d: empty port", index)
+	case hop.Channel == "":
+		return nil, fmt.Errorf("hop %d: empty channel", index)
+	}
+	if hop.Timeout != "" {
+		if d, err := time.ParseDuration(hop.Timeout); err != nil || d <= 0 {
+			return nil, errors.Join(fmt.Errorf("hop %d: invalid timeout %q", index, hop.Timeout), err)
+		}
+	}
+
+	memo := &pfmMemo{Forward: pfmForward{
+		Receiver: hop.Receiver,
+		Port:     hop.Port,
+		Channel:  hop.Channel,
+		Timeout:  hop.Timeout,
+		Retries:  hop.Retries,
+	}}
+	if hop.Next != nil {
+		next, err := hop.Next.memo(index + 1)
+		if err != nil {
+			return nil, err
+		}
+		memo.Forward.Next = next
+	}
+	return memo, nil
+}
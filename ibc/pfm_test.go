@@ -0,0 +1,35 @@
+package ibc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPFMMemo(t *testing.T) {
+	t.Parallel()
+
+	memo, err := BuildPFMMemo("cosmos1receiver", "transfer", "channel-0", nil, "", 0)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"forward":{"receiver":"cosmos1receiver","port":"transfer","channel":"channel-0","retries":0}}`, memo)
+
+	next := &PFMHop{
+		Receiver: "rol1receiver",
+		Port:     "transfer",
+		Channel:  "channel-3",
+		Timeout:  "5m",
+		Retries:  1,
+		Next:     &PFMHop{Receiver: "osmo1receiver", Port: "transfer", Channel: "channel-7"},
+	}
+	memo, err = BuildPFMMemo("dym1pfm", "transfer", "channel-1", next, "10m", 2)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"forward":{"receiver":"dym1pfm","port":"transfer","channel":"channel-1","timeout":"10m","retries":2,
+		"next":{"forward":{"receiver":"rol1receiver","port":"transfer","channel":"channel-3","timeout":"5m","retries":1,
+		"next":{"forward":{"receiver":"osmo1receiver","port":"transfer","channel":"channel-7","retries":0}}}}}}`, memo)
+
+	_, err = BuildPFMMemo("dym1pfm", "transfer", "channel-1", &PFMHop{Receiver: "rol1receiver", Port: "transfer"}, "", 0)
+	require.ErrorContains(t, err, "hop 2: empty channel")
+
+	_, err = BuildPFMMemo("dym1pfm", "transfer", "channel-1", nil, "soon", 0)
+	require.ErrorContains(t, err, "invalid timeout")
+}
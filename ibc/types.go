@@ -54,6 +54,8 @@ type ChainConfig struct {
 	UsingChainIDFlagCLI bool `yaml:"using-chain-id-flag-cli"`
 	// CoinDecimals for the chains base micro/nano/atto token configuration.
 	CoinDecimals *int64
+	// Commission rate of the genesis validators, e.g. 0.10. Uses the chain binary default when empty.
+	CommissionRate string `yaml:"commission-rate"`
 }
 
 func (c ChainConfig) Clone() ChainConfig {
@@ -346,7 +346,7 @@ func (r *DockerRelayer) StartRelayer(ctx context.Context, rep ibc.RelayerExecRep
 
 	if err := r.containerLifecycle.CreateContainer(
 		ctx, r.testName, r.networkID, containerImage, nil,
-		r.Bind(), r.HostName(joinedPaths), cmd, nil,
+		r.Bind(), r.HostName(joinedPaths), cmd, nil, nil,
 	); err != nil {
 		return err
 	}